@@ -0,0 +1,307 @@
+// Package ddl 根据与数据库无关的表定义生成 MySQL 系、DM8、Kingbase、PostgreSQL、SQLite 的 DDL 语句
+package ddl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+// Type 为与数据库无关的列类型
+type Type string
+
+const (
+	Bool     Type = "bool"
+	SmallInt Type = "smallint"
+	Int      Type = "int"
+	BigInt   Type = "bigint"
+	Float    Type = "float"
+	Double   Type = "double"
+	// Decimal 需通过 Column.Size、Column.Scale 指定精度和小数位数
+	Decimal Type = "decimal"
+	// String 为变长字符串，Column.Size 为长度，默认为 255
+	String Type = "string"
+	// Text 为不限长度的文本
+	Text  Type = "text"
+	Bytes Type = "bytes"
+	JSON  Type = "json"
+	Date  Type = "date"
+	Time  Type = "time"
+	// DateTime 为不带时区的日期时间，Column.Size 为秒的小数位数
+	DateTime Type = "datetime"
+)
+
+// Expr 为原样输出的默认值表达式
+type Expr string
+
+// CurrentTimestamp 为当前时间的默认值，按数据库转换为 CURRENT_TIMESTAMP 或 SYSDATE
+const CurrentTimestamp Expr = "CURRENT_TIMESTAMP"
+
+// Column 为列定义
+type Column struct {
+	Name string
+	Type Type
+	// Size 为字符串长度、数值精度或时间的小数位数
+	Size  int
+	Scale int
+	// Nullable 为 false 时生成 NOT NULL
+	Nullable bool
+	// Default 为默认值，string 转换为字符串字面量，bool 按数据库转换，Expr 原样输出，nil 表示没有默认值
+	Default any
+	// AutoIncrement 为自增列，必须为 SmallInt、Int 或 BigInt 且为表的唯一主键列
+	AutoIncrement bool
+	Comment       string
+}
+
+// Index 为索引定义，Name 为空时按表名和列名生成 idx_<表名>_<列名> 或 uk_<表名>_<列名>
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// Table 为表定义
+type Table struct {
+	Name       string
+	Columns    []Column
+	PrimaryKey []string
+	Indexes    []Index
+	Comment    string
+}
+
+// Generator 按数据库类型生成 DDL，标识符按方言引用，Kingbase mysql 模式使用反引号
+type Generator struct {
+	dialect rds.Dialect
+	family  string
+}
+
+// 类型映射相同的数据库分为一组
+const (
+	familyMySQL    = "MYSQL"
+	familyDM       = "DM8"
+	familyPostgres = "POSTGRES"
+	familySQLite   = "SQLITE"
+)
+
+// New 按方言创建 Generator，不支持的数据库类型返回 *driver.UnsupportedDBTypeError
+func New(d rds.Dialect) (*Generator, error) {
+	g := &Generator{dialect: d}
+	switch d.Name() {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		g.family = familyMySQL
+	case "DM8":
+		g.family = familyDM
+	case "KDB9", "POSTGRES", "OPENGAUSS":
+		g.family = familyPostgres
+	case "SQLITE":
+		g.family = familySQLite
+	default:
+		return nil, &rds.UnsupportedDBTypeError{DBType: d.Name(),
+			Supported: []string{"DM8", "GOLDENDB", "KDB9", "MARIADB", "MYSQL", "OCEANBASE", "OPENGAUSS", "POSTGRES", "SQLITE", "TIDB"}}
+	}
+	return g, nil
+}
+
+// NewFromDB 按连接池的方言创建 Generator
+func NewFromDB(ctx context.Context, db *sqlx.DB) (*Generator, error) {
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(d)
+}
+
+// CreateTable 返回建表语句，之后依次为建索引语句和 COMMENT ON 语句（MySQL 系的注释写在建表语句中）
+func (g *Generator) CreateTable(t *Table) ([]string, error) {
+	if t.Name == "" || len(t.Columns) == 0 {
+		return nil, errors.New("ddl: table requires a name and columns")
+	}
+	q := g.dialect.QuoteIdentifier
+	defs := make([]string, 0, len(t.Columns)+1)
+	inlinePK := false
+	for _, c := range t.Columns {
+		if c.AutoIncrement && (len(t.PrimaryKey) != 1 || t.PrimaryKey[0] != c.Name) {
+			return nil, fmt.Errorf("ddl: auto-increment column %s must be the only primary key column", c.Name)
+		}
+		def, err := g.columnDefinition(c)
+		if err != nil {
+			return nil, err
+		}
+		// SQLite 的自增列只能写为 INTEGER PRIMARY KEY AUTOINCREMENT
+		inlinePK = inlinePK || (c.AutoIncrement && g.family == familySQLite)
+		defs = append(defs, def)
+	}
+	if len(t.PrimaryKey) > 0 && !inlinePK {
+		defs = append(defs, "PRIMARY KEY ("+g.quoteColumns(t.PrimaryKey)+")")
+	}
+
+	stmt := "CREATE TABLE " + q(t.Name) + " (" + strings.Join(defs, ", ") + ")"
+	if g.family == familyMySQL && t.Comment != "" {
+		stmt += " COMMENT=" + g.dialect.QuoteLiteral(t.Comment)
+	}
+	statements := []string{stmt}
+	for _, idx := range t.Indexes {
+		s, err := g.CreateIndex(t.Name, idx)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+	if t.Comment != "" && g.commentOn() {
+		statements = append(statements, "COMMENT ON TABLE "+q(t.Name)+" IS "+g.dialect.QuoteLiteral(t.Comment))
+	}
+	for _, c := range t.Columns {
+		if c.Comment != "" && g.commentOn() {
+			statements = append(statements, g.commentColumn(t.Name, c))
+		}
+	}
+	return statements, nil
+}
+
+// DropTable 返回删除表的语句
+func (g *Generator) DropTable(table string) string {
+	return "DROP TABLE " + g.dialect.QuoteIdentifier(table)
+}
+
+// CreateIndex 返回建索引语句
+func (g *Generator) CreateIndex(table string, idx Index) (string, error) {
+	if len(idx.Columns) == 0 {
+		return "", fmt.Errorf("ddl: index %s of table %s requires columns", idx.Name, table)
+	}
+	stmt := "CREATE INDEX "
+	if idx.Unique {
+		stmt = "CREATE UNIQUE INDEX "
+	}
+	return stmt + g.dialect.QuoteIdentifier(indexName(table, idx)) + " ON " + g.dialect.QuoteIdentifier(table) +
+		" (" + g.quoteColumns(idx.Columns) + ")", nil
+}
+
+// DropIndex 返回删除索引的语句，MySQL 系的索引属于表，其它数据库的索引属于模式
+func (g *Generator) DropIndex(table, name string) string {
+	stmt := "DROP INDEX " + g.dialect.QuoteIdentifier(name)
+	if g.family == familyMySQL {
+		stmt += " ON " + g.dialect.QuoteIdentifier(table)
+	}
+	return stmt
+}
+
+// AddColumn 返回增加列的语句，列有注释且数据库使用 COMMENT ON 时包含两条语句，自增列不能通过此方法增加
+func (g *Generator) AddColumn(table string, c Column) ([]string, error) {
+	if c.AutoIncrement {
+		return nil, fmt.Errorf("ddl: cannot add auto-increment column %s", c.Name)
+	}
+	def, err := g.columnDefinition(c)
+	if err != nil {
+		return nil, err
+	}
+	statements := []string{"ALTER TABLE " + g.dialect.QuoteIdentifier(table) + " ADD COLUMN " + def}
+	if c.Comment != "" && g.commentOn() {
+		statements = append(statements, g.commentColumn(table, c))
+	}
+	return statements, nil
+}
+
+// DropColumn 返回删除列的语句
+func (g *Generator) DropColumn(table, column string) string {
+	return "ALTER TABLE " + g.dialect.QuoteIdentifier(table) + " DROP COLUMN " + g.dialect.QuoteIdentifier(column)
+}
+
+func (g *Generator) commentOn() bool {
+	return g.family == familyDM || g.family == familyPostgres
+}
+
+func (g *Generator) commentColumn(table string, c Column) string {
+	return "COMMENT ON COLUMN " + g.dialect.QuoteIdentifier(table) + "." + g.dialect.QuoteIdentifier(c.Name) +
+		" IS " + g.dialect.QuoteLiteral(c.Comment)
+}
+
+func (g *Generator) quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = g.dialect.QuoteIdentifier(c)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// columnDefinition 返回列定义，顺序为 名字 类型 [自增] [DEFAULT] [NOT NULL] [AUTO_INCREMENT] [COMMENT]，
+// DM 要求 DEFAULT 在 NOT NULL 之前
+func (g *Generator) columnDefinition(c Column) (string, error) {
+	if c.Name == "" {
+		return "", errors.New("ddl: column requires a name")
+	}
+	typ, err := g.columnType(c)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{g.dialect.QuoteIdentifier(c.Name), typ}
+	if c.AutoIncrement {
+		switch c.Type {
+		case SmallInt, Int, BigInt:
+		default:
+			return "", fmt.Errorf("ddl: auto-increment column %s must be an integer", c.Name)
+		}
+		switch g.family {
+		case familyDM:
+			parts = append(parts, "IDENTITY(1, 1)")
+		case familySQLite:
+			// SQLite 只有 INTEGER PRIMARY KEY 为 rowid 的别名
+			return g.dialect.QuoteIdentifier(c.Name) + " INTEGER PRIMARY KEY AUTOINCREMENT", nil
+		}
+	}
+	if c.Default != nil && !c.AutoIncrement {
+		def, err := g.defaultValue(c.Default)
+		if err != nil {
+			return "", fmt.Errorf("ddl: column %s: %w", c.Name, err)
+		}
+		parts = append(parts, "DEFAULT "+def)
+	}
+	if !c.Nullable || c.AutoIncrement {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.AutoIncrement && g.family == familyMySQL {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if c.Comment != "" && g.family == familyMySQL {
+		parts = append(parts, "COMMENT "+g.dialect.QuoteLiteral(c.Comment))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (g *Generator) defaultValue(v any) (string, error) {
+	switch v := v.(type) {
+	case Expr:
+		if v == CurrentTimestamp {
+			return g.dialect.CurrentTimestamp(), nil
+		}
+		return string(v), nil
+	case string:
+		return g.dialect.QuoteLiteral(v), nil
+	case bool:
+		return g.dialect.BoolLiteral(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported default value %T", v)
+}
+
+func indexName(table string, idx Index) string {
+	if idx.Name != "" {
+		return idx.Name
+	}
+	prefix := "idx_"
+	if idx.Unique {
+		prefix = "uk_"
+	}
+	return prefix + table + "_" + strings.Join(idx.Columns, "_")
+}
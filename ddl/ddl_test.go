@@ -0,0 +1,180 @@
+package ddl
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	_ "github.com/kweaver-ai/proton-rds-sdk-go/driver/sqlite"
+	"github.com/kweaver-ai/proton-rds-sdk-go/schema"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+var clientTable = &Table{
+	Name: "t_client",
+	Columns: []Column{
+		{Name: "f_id", Type: BigInt, AutoIncrement: true},
+		{Name: "f_name", Type: String, Size: 64, Default: "", Comment: "名称"},
+		{Name: "f_secret", Type: Text},
+		{Name: "f_skip_consent", Type: Bool, Default: false},
+		{Name: "f_audience", Type: JSON, Nullable: true},
+		{Name: "f_price", Type: Decimal, Size: 10, Scale: 2, Default: 0},
+		{Name: "f_created_at", Type: DateTime, Default: CurrentTimestamp},
+	},
+	PrimaryKey: []string{"f_id"},
+	Indexes:    []Index{{Columns: []string{"f_name"}, Unique: true}},
+	Comment:    "客户端",
+}
+
+func TestCreateTable(t *testing.T) {
+	tests := []struct {
+		dialect rds.Dialect
+		want    []string
+	}{
+		{mustDialect("MYSQL"), []string{
+			"CREATE TABLE `t_client` (`f_id` BIGINT NOT NULL AUTO_INCREMENT, " +
+				"`f_name` VARCHAR(64) DEFAULT '' NOT NULL COMMENT '名称', `f_secret` LONGTEXT NOT NULL, " +
+				"`f_skip_consent` TINYINT(1) DEFAULT FALSE NOT NULL, `f_audience` JSON, " +
+				"`f_price` DECIMAL(10, 2) DEFAULT 0 NOT NULL, `f_created_at` DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL, " +
+				"PRIMARY KEY (`f_id`)) COMMENT='客户端'",
+			"CREATE UNIQUE INDEX `uk_t_client_f_name` ON `t_client` (`f_name`)",
+		}},
+		{mustDialect("DM8"), []string{
+			`CREATE TABLE "t_client" ("f_id" BIGINT IDENTITY(1, 1) NOT NULL, ` +
+				`"f_name" VARCHAR(64) DEFAULT '' NOT NULL, "f_secret" CLOB NOT NULL, ` +
+				`"f_skip_consent" BIT DEFAULT 0 NOT NULL, "f_audience" CLOB, ` +
+				`"f_price" DECIMAL(10, 2) DEFAULT 0 NOT NULL, "f_created_at" TIMESTAMP DEFAULT SYSDATE NOT NULL, ` +
+				`PRIMARY KEY ("f_id"))`,
+			`CREATE UNIQUE INDEX "uk_t_client_f_name" ON "t_client" ("f_name")`,
+			`COMMENT ON TABLE "t_client" IS '客户端'`,
+			`COMMENT ON COLUMN "t_client"."f_name" IS '名称'`,
+		}},
+		{rds.KingbaseDialect("oracle"), []string{
+			`CREATE TABLE "t_client" ("f_id" BIGSERIAL NOT NULL, ` +
+				`"f_name" VARCHAR(64) DEFAULT '' NOT NULL, "f_secret" TEXT NOT NULL, ` +
+				`"f_skip_consent" BOOLEAN DEFAULT FALSE NOT NULL, "f_audience" JSON, ` +
+				`"f_price" NUMERIC(10, 2) DEFAULT 0 NOT NULL, "f_created_at" TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL, ` +
+				`PRIMARY KEY ("f_id"))`,
+			`CREATE UNIQUE INDEX "uk_t_client_f_name" ON "t_client" ("f_name")`,
+			`COMMENT ON TABLE "t_client" IS '客户端'`,
+			`COMMENT ON COLUMN "t_client"."f_name" IS '名称'`,
+		}},
+		{rds.KingbaseDialect("mysql"), []string{
+			"CREATE TABLE `t_client` (`f_id` BIGSERIAL NOT NULL, " +
+				"`f_name` VARCHAR(64) DEFAULT '' NOT NULL, `f_secret` TEXT NOT NULL, " +
+				"`f_skip_consent` BOOLEAN DEFAULT FALSE NOT NULL, `f_audience` JSON, " +
+				"`f_price` NUMERIC(10, 2) DEFAULT 0 NOT NULL, `f_created_at` TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL, " +
+				"PRIMARY KEY (`f_id`))",
+			"CREATE UNIQUE INDEX `uk_t_client_f_name` ON `t_client` (`f_name`)",
+			"COMMENT ON TABLE `t_client` IS '客户端'",
+			"COMMENT ON COLUMN `t_client`.`f_name` IS '名称'",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			g, err := New(tt.dialect)
+			assert.Nil(t, err)
+			statements, err := g.CreateTable(clientTable)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, statements)
+		})
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	mysql, _ := New(mustDialect("MYSQL"))
+	dm, _ := New(mustDialect("DM8"))
+	c := Column{Name: "f_age", Type: Int, Nullable: true, Comment: "年龄"}
+
+	statements, err := mysql.AddColumn("t_user", c)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ALTER TABLE `t_user` ADD COLUMN `f_age` INT COMMENT '年龄'"}, statements)
+	statements, err = dm.AddColumn("t_user", c)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`ALTER TABLE "t_user" ADD COLUMN "f_age" INT`, `COMMENT ON COLUMN "t_user"."f_age" IS '年龄'`}, statements)
+
+	assert.Equal(t, "ALTER TABLE `t_user` DROP COLUMN `f_age`", mysql.DropColumn("t_user", "f_age"))
+	assert.Equal(t, "DROP INDEX `idx_age` ON `t_user`", mysql.DropIndex("t_user", "idx_age"))
+	assert.Equal(t, `DROP INDEX "idx_age"`, dm.DropIndex("t_user", "idx_age"))
+	assert.Equal(t, `DROP TABLE "t_user"`, dm.DropTable("t_user"))
+
+	_, err = mysql.AddColumn("t_user", Column{Name: "f_id", Type: BigInt, AutoIncrement: true})
+	assert.NotNil(t, err)
+}
+
+func TestInvalid(t *testing.T) {
+	g, _ := New(mustDialect("MYSQL"))
+	tests := []struct {
+		name  string
+		table *Table
+	}{
+		{"no columns", &Table{Name: "t"}},
+		{"auto increment without primary key", &Table{Name: "t", Columns: []Column{{Name: "a", Type: Int, AutoIncrement: true}}}},
+		{"auto increment string", &Table{Name: "t", Columns: []Column{{Name: "a", Type: String, AutoIncrement: true}}, PrimaryKey: []string{"a"}}},
+		{"decimal without size", &Table{Name: "t", Columns: []Column{{Name: "a", Type: Decimal}}}},
+		{"unknown type", &Table{Name: "t", Columns: []Column{{Name: "a", Type: "uuid"}}}},
+		{"unsupported default", &Table{Name: "t", Columns: []Column{{Name: "a", Type: Int, Default: []int{1}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.CreateTable(tt.table)
+			assert.NotNil(t, err)
+		})
+	}
+
+	_, err := New(unknownDialect{mustDialect("MYSQL")})
+	assert.True(t, errors.Is(err, rds.ErrUnsupportedDBType))
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.NewDB(&sqlx.DBConfig{
+		User:     "user",
+		Password: "pwd",
+		Host:     "localhost",
+		Database: filepath.Join(t.TempDir(), "test.db"),
+		DBType:   "sqlite",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := NewFromDB(ctx, db)
+	assert.Nil(t, err)
+	statements, err := g.CreateTable(clientTable)
+	assert.Nil(t, err)
+	for _, stmt := range statements {
+		_, err = db.Exec(stmt)
+		assert.Nil(t, err, stmt)
+	}
+	_, err = db.Exec("INSERT INTO t_client (f_secret) VALUES ('s')")
+	assert.Nil(t, err)
+
+	i, _ := schema.NewFromDB(ctx, db)
+	table, err := i.Describe(ctx, "", "t_client")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(table.Columns))
+	assert.True(t, table.Columns[0].AutoIncrement)
+	assert.Equal(t, "varchar", table.Columns[1].DataType)
+	assert.True(t, table.Columns[4].Nullable)
+	assert.Equal(t, []string{"f_name"}, table.Indexes[0].Columns)
+}
+
+func mustDialect(dbType string) rds.Dialect {
+	d, ok := rds.DialectFor(dbType)
+	if !ok {
+		panic(dbType)
+	}
+	return d
+}
+
+type unknownDialect struct {
+	rds.Dialect
+}
+
+func (unknownDialect) Name() string { return "ORACLE" }
@@ -0,0 +1,176 @@
+package ddl
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableOf 按结构体的字段生成表定义，v 为结构体或其指针。列名取 db 标签，没有时为小写的字段名，
+// db:"-" 的字段被忽略，匿名嵌入的结构体字段展开。ddl 标签以分号分隔，支持：
+//
+//	type:string   列类型，默认按 Go 类型推断
+//	size:64       长度或精度
+//	scale:2       小数位数
+//	pk            主键，多个字段按字段顺序组成联合主键
+//	auto_increment
+//	null          可为空，指针和 sql.Null* 类型默认可为空
+//	default:0     默认值，current_timestamp 表示当前时间，字符串需带单引号，如 default:'a'
+//	index:idx_a   普通索引，同名的多个字段组成联合索引，名字可省略
+//	unique:uk_a   唯一索引，名字可省略
+//	comment:说明
+func TableOf(name string, v any) (*Table, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ddl: %T is not a struct", v)
+	}
+	t := &Table{Name: name}
+	indexes := make(map[string]int)
+	if err := t.addFields(typ, indexes); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Table) addFields(typ reflect.Type, indexes map[string]int) error {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct && fieldType(f.Type) == "" {
+			if err := t.addFields(f.Type, indexes); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c := Column{Name: tag}
+		if c.Name == "" {
+			c.Name = strings.ToLower(f.Name)
+		}
+		c.Type, c.Nullable = fieldType(f.Type), nullable(f.Type)
+		for _, opt := range strings.Split(f.Tag.Get("ddl"), ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(opt), ":")
+			var err error
+			switch strings.ToLower(key) {
+			case "":
+			case "type":
+				c.Type = Type(strings.ToLower(value))
+			case "size":
+				c.Size, err = strconv.Atoi(value)
+			case "scale":
+				c.Scale, err = strconv.Atoi(value)
+			case "pk":
+				t.PrimaryKey = append(t.PrimaryKey, c.Name)
+			case "auto_increment":
+				c.AutoIncrement = true
+			case "null":
+				c.Nullable = true
+			case "default":
+				c.Default = parseDefault(value)
+			case "index", "unique":
+				unique := strings.EqualFold(key, "unique")
+				if value == "" {
+					t.Indexes = append(t.Indexes, Index{Columns: []string{c.Name}, Unique: unique})
+				} else if k, ok := indexes[value]; ok {
+					t.Indexes[k].Columns = append(t.Indexes[k].Columns, c.Name)
+				} else {
+					indexes[value] = len(t.Indexes)
+					t.Indexes = append(t.Indexes, Index{Name: value, Columns: []string{c.Name}, Unique: unique})
+				}
+			case "comment":
+				c.Comment = value
+			default:
+				err = fmt.Errorf("unknown option %q", key)
+			}
+			if err != nil {
+				return fmt.Errorf("ddl: field %s: %w", f.Name, err)
+			}
+		}
+		if c.Type == "" {
+			return fmt.Errorf("ddl: cannot infer the column type of field %s (%s), use the type option", f.Name, f.Type)
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	return nil
+}
+
+// parseDefault 将标签中的默认值转换为 Column.Default，'a' 为字符串，true、false 为布尔值，其它原样输出
+func parseDefault(s string) any {
+	switch {
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	case strings.EqualFold(s, "current_timestamp"):
+		return CurrentTimestamp
+	case strings.EqualFold(s, "true"):
+		return true
+	case strings.EqualFold(s, "false"):
+		return false
+	}
+	return Expr(s)
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	nullTypes      = map[reflect.Type]Type{
+		reflect.TypeOf(sql.NullBool{}):    Bool,
+		reflect.TypeOf(sql.NullInt16{}):   SmallInt,
+		reflect.TypeOf(sql.NullInt32{}):   Int,
+		reflect.TypeOf(sql.NullInt64{}):   BigInt,
+		reflect.TypeOf(sql.NullFloat64{}): Double,
+		reflect.TypeOf(sql.NullString{}):  String,
+		reflect.TypeOf(sql.NullTime{}):    DateTime,
+	}
+)
+
+// fieldType 按 Go 类型推断列类型，无法推断时返回空
+func fieldType(typ reflect.Type) Type {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if t, ok := nullTypes[typ]; ok {
+		return t
+	}
+	switch typ {
+	case timeType:
+		return DateTime
+	case rawMessageType:
+		return JSON
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return Bool
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return SmallInt
+	case reflect.Int32, reflect.Uint16:
+		return Int
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return BigInt
+	case reflect.Float32:
+		return Float
+	case reflect.Float64:
+		return Double
+	case reflect.String:
+		return String
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return Bytes
+		}
+	}
+	return ""
+}
+
+func nullable(typ reflect.Type) bool {
+	_, ok := nullTypes[typ]
+	return ok || typ.Kind() == reflect.Pointer
+}
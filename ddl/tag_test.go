@@ -0,0 +1,69 @@
+package ddl
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type model struct {
+	CreatedAt time.Time `db:"f_created_at" ddl:"default:current_timestamp"`
+}
+
+type client struct {
+	ID         int64           `db:"f_id" ddl:"pk;auto_increment"`
+	Name       string          `db:"f_name" ddl:"size:64;default:'';unique:uk_name;comment:名称"`
+	Nid        string          `db:"f_nid" ddl:"size:36;unique:uk_name"`
+	Secret     string          `db:"f_secret" ddl:"type:text"`
+	Skip       bool            `db:"f_skip_consent" ddl:"default:false"`
+	Audience   json.RawMessage `db:"f_audience" ddl:"null"`
+	Lifespan   *int64          `db:"f_lifespan"`
+	Owner      sql.NullString  `db:"f_owner" ddl:"index"`
+	Price      float64         `db:"f_price" ddl:"type:decimal;size:10;scale:2;default:0"`
+	Ignored    string          `db:"-"`
+	unexported string
+	model
+}
+
+func TestTableOf(t *testing.T) {
+	table, err := TableOf("t_client", &client{})
+	assert.Nil(t, err)
+	assert.Equal(t, &Table{
+		Name: "t_client",
+		Columns: []Column{
+			{Name: "f_id", Type: BigInt, AutoIncrement: true},
+			{Name: "f_name", Type: String, Size: 64, Default: "", Comment: "名称"},
+			{Name: "f_nid", Type: String, Size: 36},
+			{Name: "f_secret", Type: Text},
+			{Name: "f_skip_consent", Type: Bool, Default: false},
+			{Name: "f_audience", Type: JSON, Nullable: true},
+			{Name: "f_lifespan", Type: BigInt, Nullable: true},
+			{Name: "f_owner", Type: String, Nullable: true},
+			{Name: "f_price", Type: Decimal, Size: 10, Scale: 2, Default: Expr("0")},
+			{Name: "f_created_at", Type: DateTime, Default: CurrentTimestamp},
+		},
+		PrimaryKey: []string{"f_id"},
+		Indexes: []Index{
+			{Name: "uk_name", Columns: []string{"f_name", "f_nid"}, Unique: true},
+			{Columns: []string{"f_owner"}},
+		},
+	}, table)
+
+	_, err = TableOf("t", 1)
+	assert.NotNil(t, err)
+	_, err = TableOf("t", struct {
+		A map[string]int
+	}{})
+	assert.NotNil(t, err)
+	_, err = TableOf("t", struct {
+		A int `ddl:"size:x"`
+	}{})
+	assert.NotNil(t, err)
+	_, err = TableOf("t", struct {
+		A int `ddl:"primary"`
+	}{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,117 @@
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// nativeTypes 为各组数据库中与参数无关的类型，Kingbase 各模式均使用 PostgreSQL 的类型名
+var nativeTypes = map[string]map[Type]string{
+	familyMySQL: {
+		Bool:     "TINYINT(1)",
+		SmallInt: "SMALLINT",
+		Int:      "INT",
+		BigInt:   "BIGINT",
+		Float:    "FLOAT",
+		Double:   "DOUBLE",
+		Text:     "LONGTEXT",
+		Bytes:    "LONGBLOB",
+		JSON:     "JSON",
+		Date:     "DATE",
+		Time:     "TIME",
+		DateTime: "DATETIME",
+	},
+	familyDM: {
+		Bool:     "BIT",
+		SmallInt: "SMALLINT",
+		Int:      "INT",
+		BigInt:   "BIGINT",
+		Float:    "REAL",
+		Double:   "DOUBLE",
+		Text:     "CLOB",
+		Bytes:    "BLOB",
+		// DM 没有 JSON 类型，JSON 函数作用于字符串
+		JSON:     "CLOB",
+		Date:     "DATE",
+		Time:     "TIME",
+		DateTime: "TIMESTAMP",
+	},
+	familyPostgres: {
+		Bool:     "BOOLEAN",
+		SmallInt: "SMALLINT",
+		Int:      "INTEGER",
+		BigInt:   "BIGINT",
+		Float:    "REAL",
+		Double:   "DOUBLE PRECISION",
+		Text:     "TEXT",
+		Bytes:    "BYTEA",
+		JSON:     "JSON",
+		Date:     "DATE",
+		Time:     "TIME WITHOUT TIME ZONE",
+		DateTime: "TIMESTAMP WITHOUT TIME ZONE",
+	},
+	familySQLite: {
+		Bool:     "BOOLEAN",
+		SmallInt: "SMALLINT",
+		Int:      "INTEGER",
+		BigInt:   "BIGINT",
+		Float:    "REAL",
+		Double:   "DOUBLE",
+		Text:     "TEXT",
+		Bytes:    "BLOB",
+		JSON:     "TEXT",
+		Date:     "DATE",
+		Time:     "TIME",
+		DateTime: "DATETIME",
+	},
+}
+
+// postgresSerials 为 PostgreSQL 系自增列的类型
+var postgresSerials = map[Type]string{
+	SmallInt: "SMALLSERIAL",
+	Int:      "SERIAL",
+	BigInt:   "BIGSERIAL",
+}
+
+// columnType 返回列在数据库中的类型
+func (g *Generator) columnType(c Column) (string, error) {
+	switch c.Type {
+	case String:
+		size := c.Size
+		if size <= 0 {
+			size = 255
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")", nil
+	case Decimal:
+		if c.Size <= 0 {
+			return "", fmt.Errorf("ddl: decimal column %s requires a size", c.Name)
+		}
+		name := "DECIMAL"
+		if g.family == familyPostgres {
+			name = "NUMERIC"
+		}
+		return name + "(" + strconv.Itoa(c.Size) + ", " + strconv.Itoa(c.Scale) + ")", nil
+	}
+	if c.AutoIncrement && g.family == familyPostgres {
+		if s, ok := postgresSerials[c.Type]; ok {
+			return s, nil
+		}
+	}
+	typ, ok := nativeTypes[g.family][c.Type]
+	if !ok {
+		return "", fmt.Errorf("ddl: unsupported type %q of column %s", c.Type, c.Name)
+	}
+	if c.Type == DateTime && c.Size > 0 {
+		return withPrecision(typ, c.Size), nil
+	}
+	return typ, nil
+}
+
+// withPrecision 为时间类型加上秒的小数位数，TIMESTAMP WITHOUT TIME ZONE 写为 TIMESTAMP(n) WITHOUT TIME ZONE
+func withPrecision(typ string, n int) string {
+	p := "(" + strconv.Itoa(n) + ")"
+	if typ == "TIMESTAMP WITHOUT TIME ZONE" {
+		return "TIMESTAMP" + p + " WITHOUT TIME ZONE"
+	}
+	return typ + p
+}
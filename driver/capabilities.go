@@ -0,0 +1,248 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Capabilities 为数据库支持的特性和限制，由数据库类型、服务端版本和 Kingbase 的 database_mode 决定
+type Capabilities struct {
+	DBType string
+	// Version 为解析后的服务端版本号，如 8.0.36、10.6.12（MariaDB）、7.1.0（TiDB）、8.6.8.14（Kingbase）、8.1.2.128（DM8）
+	Version string
+	// Mode 为服务端的兼容模式，Kingbase 为 database_mode
+	Mode string
+
+	// LastInsertID 为 sql.Result.LastInsertId 是否可用，Kingbase 需在 DSN 中设置 get_last_insert_id=yes
+	LastInsertID bool
+	// Returning 为是否支持 INSERT/UPDATE/DELETE ... RETURNING
+	Returning bool
+	// Savepoints 为是否支持 SAVEPOINT
+	Savepoints bool
+	// JSON 为是否支持 JSON 列类型和函数
+	JSON bool
+	// SkipLocked 为是否支持 SELECT ... FOR UPDATE SKIP LOCKED
+	SkipLocked bool
+	// WindowFunctions 为是否支持窗口函数
+	WindowFunctions bool
+	// RowValueComparison 为是否支持 (a, b) > (?, ?) 形式的行比较
+	RowValueComparison bool
+
+	// MaxParams 为一条语句的参数个数上限，Kingbase、PostgreSQL、openGauss 为 gokb 协议实现的上限 65535
+	MaxParams int
+	// MaxIdentifierLength 为标识符的最大长度，0 表示不限制
+	MaxIdentifierLength int
+	// UpperCaseIdentifiers 为未加引号的标识符是否转换为大写，如 DM8 和 oracle 模式的 Kingbase
+	UpperCaseIdentifiers bool
+}
+
+// CapabilitiesOf 按 ServerInfo 返回的服务端版本和兼容模式，返回连接池支持的特性和限制
+func CapabilitiesOf(ctx context.Context, db *sql.DB) (Capabilities, error) {
+	server, err := ServerInfo(ctx, db)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	c := CapabilitiesFor(server.DBType, server.RawVersion, server.Mode)
+	if server.DBType != "KDB9" {
+		return c, nil
+	}
+
+	// get_last_insert_id 为连接参数，需从连接上读取
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	defer conn.Close()
+	err = conn.Raw(func(dc any) error {
+		if m, ok := UnwrapConn(dc).(interface{ LastInsertIDEnabled() bool }); ok {
+			c.LastInsertID = m.LastInsertIDEnabled()
+		}
+		return nil
+	})
+	return c, err
+}
+
+// CapabilitiesFor 按数据库类型、服务端版本串和 Kingbase 的 database_mode 返回支持的特性和限制，
+// 版本串为 SELECT VERSION() 等语句的原始结果，未知的数据库类型返回的特性均为 false
+func CapabilitiesFor(dbType, version, mode string) Capabilities {
+	dbType = NormalizeDBType(dbType)
+	v := parseVersion(dbType, version)
+	c := Capabilities{DBType: dbType, Version: v.String(), Mode: strings.ToLower(mode)}
+	switch dbType {
+	case "MYSQL", "GOLDENDB":
+		c.LastInsertID = true
+		c.Savepoints = true
+		c.JSON = v.atLeast(5, 7, 8)
+		c.SkipLocked = v.atLeast(8, 0, 1)
+		c.WindowFunctions = v.atLeast(8, 0)
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 64
+	case "MARIADB":
+		c.LastInsertID = true
+		c.Returning = v.atLeast(10, 5)
+		c.Savepoints = true
+		c.JSON = v.atLeast(10, 2, 7)
+		c.SkipLocked = v.atLeast(10, 6)
+		c.WindowFunctions = v.atLeast(10, 2)
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 64
+	case "TIDB":
+		c.LastInsertID = true
+		c.Savepoints = v.atLeast(6, 2)
+		c.JSON = true
+		c.WindowFunctions = v.atLeast(3, 0)
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 64
+	case "OCEANBASE":
+		c.LastInsertID = true
+		c.Savepoints = true
+		c.JSON = true
+		c.WindowFunctions = true
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 64
+	case "DM8":
+		c.LastInsertID = true
+		c.Savepoints = true
+		c.JSON = true
+		c.SkipLocked = true
+		c.WindowFunctions = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 128
+		c.UpperCaseIdentifiers = true
+	case "KDB9":
+		// LastInsertID 由连接的 get_last_insert_id 决定，见 CapabilitiesOf；gokb 在各模式下都通过 RETURNING * 取得自增值，
+		// RETURNING 与 database_mode 无关
+		c.Returning = true
+		c.Savepoints = true
+		// V8R3 起支持 JSON 和 SKIP LOCKED
+		c.JSON = v.atLeast(8, 3)
+		c.SkipLocked = v.atLeast(8, 3)
+		c.WindowFunctions = true
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 63
+		// oracle 模式下未加引号的标识符按 Oracle 的规则转换为大写
+		c.UpperCaseIdentifiers = c.Mode == "oracle"
+	case "POSTGRES":
+		c.Returning = true
+		c.Savepoints = true
+		c.JSON = v.atLeast(9, 2)
+		c.SkipLocked = v.atLeast(9, 5)
+		c.WindowFunctions = true
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 63
+	case "OPENGAUSS":
+		c.Returning = true
+		c.Savepoints = true
+		c.JSON = true
+		c.WindowFunctions = true
+		c.RowValueComparison = true
+		c.MaxParams = 65535
+		c.MaxIdentifierLength = 63
+	case "SQLITE":
+		c.LastInsertID = true
+		c.Returning = v.atLeast(3, 35)
+		c.Savepoints = true
+		c.JSON = v.atLeast(3, 38)
+		c.WindowFunctions = v.atLeast(3, 25)
+		c.RowValueComparison = v.atLeast(3, 15)
+		c.MaxParams = 32766
+	}
+	return c
+}
+
+var (
+	versionPattern     = regexp.MustCompile(`\d+(\.\d+)*`)
+	dmVersionPattern   = regexp.MustCompile(`V(\d+(\.\d+)*)`)
+	tidbVersionPattern = regexp.MustCompile(`TiDB-v(\d+(\.\d+)*)`)
+	obVersionPattern   = regexp.MustCompile(`OceanBase(?:_CE)?-v(\d+(\.\d+)*)`)
+	kdbVersionPattern  = regexp.MustCompile(`V(\d+)R(\d+)(?:C(\d+))?(?:B(\d+))?`)
+	ogVersionPattern   = regexp.MustCompile(`openGauss (\d+(\.\d+)*)`)
+)
+
+// version 为按 . 分隔的版本号
+type version []int
+
+// parseVersion 从原始版本串中取出产品版本号：MariaDB 去掉兼容前缀 5.5.5-，TiDB、OceanBase 取 -v 之后的部分，
+// DM 取 V 之后的部分，KingbaseES 的 V008R006C008B0014 转换为 8.6.8.14，其它取第一个数字串
+func parseVersion(dbType, raw string) version {
+	var s string
+	switch dbType {
+	case "KDB9":
+		if m := kdbVersionPattern.FindStringSubmatch(raw); m != nil {
+			parts := make([]string, 0, 4)
+			for _, p := range m[1:] {
+				if p != "" {
+					parts = append(parts, p)
+				}
+			}
+			s = strings.Join(parts, ".")
+		} else {
+			s = versionPattern.FindString(raw)
+		}
+	case "OCEANBASE", "OPENGAUSS":
+		pattern := obVersionPattern
+		if dbType == "OPENGAUSS" {
+			pattern = ogVersionPattern
+		}
+		if m := pattern.FindStringSubmatch(raw); m != nil {
+			s = m[1]
+		} else {
+			s = versionPattern.FindString(raw)
+		}
+	case "MARIADB":
+		s = versionPattern.FindString(strings.TrimPrefix(raw, "5.5.5-"))
+	case "TIDB":
+		if m := tidbVersionPattern.FindStringSubmatch(raw); m != nil {
+			s = m[1]
+		}
+	case "DM8":
+		if m := dmVersionPattern.FindStringSubmatch(raw); m != nil {
+			s = m[1]
+		}
+	default:
+		s = versionPattern.FindString(raw)
+	}
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ".")
+	v := make(version, len(parts))
+	for i, p := range parts {
+		v[i], _ = strconv.Atoi(p)
+	}
+	return v
+}
+
+// atLeast 判断版本号是否不低于 want，版本号未知时返回 false
+func (v version) atLeast(want ...int) bool {
+	if len(v) == 0 {
+		return false
+	}
+	for i, w := range want {
+		var n int
+		if i < len(v) {
+			n = v[i]
+		}
+		if n != w {
+			return n > w
+		}
+	}
+	return true
+}
+
+func (v version) String() string {
+	s := make([]string, len(v))
+	for i, n := range v {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ".")
+}
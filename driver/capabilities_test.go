@@ -0,0 +1,43 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesFor(t *testing.T) {
+	tests := []struct {
+		dbType  string
+		raw     string
+		mode    string
+		version string
+		check   func(c Capabilities) bool
+	}{
+		{"MYSQL", "5.7.44-log", "", "5.7.44", func(c Capabilities) bool { return c.JSON && !c.SkipLocked && !c.WindowFunctions }},
+		{"MYSQL", "8.0.36", "", "8.0.36", func(c Capabilities) bool { return c.SkipLocked && c.WindowFunctions && c.LastInsertID }},
+		{"MARIADB", "5.5.5-10.6.12-MariaDB", "", "10.6.12", func(c Capabilities) bool { return c.Returning && c.SkipLocked }},
+		{"TIDB", "5.7.25-TiDB-v7.1.0", "", "7.1.0", func(c Capabilities) bool { return c.Savepoints && !c.SkipLocked }},
+		{"DM8", "DM Database Server 64 V8", "", "8", func(c Capabilities) bool {
+			return !c.RowValueComparison && c.SkipLocked && c.UpperCaseIdentifiers
+		}},
+		{"kingbase", "12.1", "Oracle", "12.1", func(c Capabilities) bool {
+			return c.Mode == "oracle" && c.Returning && !c.LastInsertID && c.MaxParams == 65535 && c.UpperCaseIdentifiers
+		}},
+		{"KDB9", "KingbaseES V008R006C008B0014 on x86_64", "mysql", "8.6.8.14", func(c Capabilities) bool {
+			return c.Mode == "mysql" && c.SkipLocked && c.JSON && !c.UpperCaseIdentifiers
+		}},
+		{"KDB9", "KingbaseES V008R002C001B0001", "pg", "8.2.1.1", func(c Capabilities) bool {
+			return !c.SkipLocked && !c.JSON && c.Returning && !c.UpperCaseIdentifiers
+		}},
+		{"SQLITE", "3.50.4", "", "3.50.4", func(c Capabilities) bool { return c.Returning && c.JSON && !c.SkipLocked }},
+		{"ORACLE", "19c", "", "19", func(c Capabilities) bool { return !c.Savepoints && c.MaxParams == 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dbType+" "+tt.raw, func(t *testing.T) {
+			c := CapabilitiesFor(tt.dbType, tt.raw, tt.mode)
+			assert.Equal(t, tt.version, c.Version)
+			assert.True(t, tt.check(c), "%+v", c)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package common
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// ErrorMapper 转换具体驱动返回的错误，不需要转换时原样返回
+type ErrorMapper func(error) error
+
+// Conn 包装具体驱动的连接，对连接、预备语句和事务返回的错误调用 ErrorMapper，
+// 具体驱动未实现的可选接口按 database/sql 的默认行为处理
+type Conn struct {
+	conn   driver.Conn
+	mapErr ErrorMapper
+}
+
+// WrapConn 包装具体驱动的连接，conn 为空时返回空
+func WrapConn(conn driver.Conn, mapErr ErrorMapper) driver.Conn {
+	if conn == nil {
+		return nil
+	}
+	return &Conn{conn: conn, mapErr: mapErr}
+}
+
+// Unwrap 返回被包装的具体驱动的连接
+func (c *Conn) Unwrap() driver.Conn {
+	return c.conn
+}
+
+func (c *Conn) err(err error) error {
+	if err == nil || err == driver.ErrSkip || err == driver.ErrBadConn {
+		return err
+	}
+	return c.mapErr(err)
+}
+
+func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return &Stmt{stmt: stmt, conn: c}, nil
+}
+
+func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	stmt, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return &Stmt{stmt: stmt, conn: c}, nil
+}
+
+func (c *Conn) Close() error {
+	return c.err(c.conn.Close())
+}
+
+func (c *Conn) Begin() (driver.Tx, error) {
+	tx, err := c.conn.Begin()
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return &Tx{tx: tx, conn: c}, nil
+}
+
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	bt, ok := c.conn.(driver.ConnBeginTx)
+	if !ok {
+		if opts.Isolation != driver.IsolationLevel(0) {
+			return nil, errors.New("sql: driver does not support non-default isolation level")
+		}
+		if opts.ReadOnly {
+			return nil, errors.New("sql: driver does not support read-only transactions")
+		}
+		return c.Begin()
+	}
+	tx, err := bt.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return &Tx{tx: tx, conn: c}, nil
+}
+
+func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	res, err := ec.ExecContext(ctx, query, args)
+	return res, c.err(err)
+}
+
+func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return c.rows(qc.QueryContext(ctx, query, args))
+}
+
+func (c *Conn) rows(rows driver.Rows, err error) (driver.Rows, error) {
+	if err != nil {
+		return nil, c.err(err)
+	}
+	return &Rows{rows: rows, conn: c}, nil
+}
+
+func (c *Conn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return c.err(p.Ping(ctx))
+	}
+	return nil
+}
+
+func (c *Conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *Conn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue 沿 Unwrap 查找实现了 NamedValueChecker 的连接，
+// 使只嵌入 driver.Conn 的包装仍能使用具体驱动的参数检查
+func (c *Conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc := c.namedValueChecker(); nvc != nil {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *Conn) namedValueChecker() driver.NamedValueChecker {
+	conn := c.conn
+	for {
+		if nvc, ok := conn.(driver.NamedValueChecker); ok {
+			return nvc
+		}
+		u, ok := conn.(interface{ Unwrap() driver.Conn })
+		if !ok {
+			return nil
+		}
+		conn = u.Unwrap()
+	}
+}
+
+// Stmt 包装具体驱动的预备语句
+type Stmt struct {
+	stmt driver.Stmt
+	conn *Conn
+}
+
+func (s *Stmt) Close() error {
+	return s.conn.err(s.stmt.Close())
+}
+
+func (s *Stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, err := s.stmt.Exec(args)
+	return res, s.conn.err(err)
+}
+
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.rows(s.stmt.Query(args))
+}
+
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if ec, ok := s.stmt.(driver.StmtExecContext); ok {
+		res, err := ec.ExecContext(ctx, args)
+		return res, s.conn.err(err)
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Exec(values)
+}
+
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if qc, ok := s.stmt.(driver.StmtQueryContext); ok {
+		return s.conn.rows(qc.QueryContext(ctx, args))
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Query(values)
+}
+
+// CheckNamedValue 优先使用预备语句的参数检查，其次使用连接的参数检查，
+// database/sql 在预备语句实现了 NamedValueChecker 时不会再检查连接
+func (s *Stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+// Tx 包装具体驱动的事务
+type Tx struct {
+	tx   driver.Tx
+	conn *Conn
+}
+
+func (t *Tx) Commit() error {
+	return t.conn.err(t.tx.Commit())
+}
+
+func (t *Tx) Rollback() error {
+	return t.conn.err(t.tx.Rollback())
+}
+
+// Rows 包装具体驱动的结果集，具体驱动未实现的可选接口返回 database/sql 的默认值
+type Rows struct {
+	rows driver.Rows
+	conn *Conn
+}
+
+func (r *Rows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *Rows) Close() error {
+	return r.conn.err(r.rows.Close())
+}
+
+func (r *Rows) Next(dest []driver.Value) error {
+	err := r.rows.Next(dest)
+	if err == io.EOF {
+		return err
+	}
+	return r.conn.err(err)
+}
+
+func (r *Rows) HasNextResultSet() bool {
+	if rs, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *Rows) NextResultSet() error {
+	rs, ok := r.rows.(driver.RowsNextResultSet)
+	if !ok {
+		return io.EOF
+	}
+	err := rs.NextResultSet()
+	if err == io.EOF {
+		return err
+	}
+	return r.conn.err(err)
+}
+
+func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r *Rows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *Rows) ColumnTypeLength(index int) (int64, bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *Rows) ColumnTypeNullable(index int) (bool, bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *Rows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if ct, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+func namedValuesToValues(named []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(named))
+	for i, nv := range named {
+		if nv.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = nv.Value
+	}
+	return values, nil
+}
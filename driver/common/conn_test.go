@@ -0,0 +1,108 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errMapped = errors.New("mapped")
+
+func mapErr(err error) error {
+	return fmt.Errorf("%w: %w", errMapped, err)
+}
+
+// fakeConn 只实现 driver.Conn，语句通过 Prepare 执行
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{conn: c}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	conn *fakeConn
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return 1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"v"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return errors.New("broken") }
+
+type fakeDriver struct {
+	conn *fakeConn
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return WrapConn(d.conn, mapErr), nil
+}
+
+func TestConn(t *testing.T) {
+	conn := &fakeConn{}
+	db := sql.OpenDB(fakeConnector{fakeDriver{conn}})
+	defer db.Close()
+
+	_, err := db.Exec("UPDATE t SET v = ?", 1)
+	assert.Nil(t, err)
+
+	// 遍历结果集时的错误同样经过 ErrorMapper
+	rows, err := db.Query("SELECT v FROM t WHERE v = ?", 1)
+	assert.Nil(t, err)
+	assert.False(t, rows.Next())
+	assert.ErrorIs(t, rows.Err(), errMapped)
+	assert.Nil(t, rows.Close())
+}
+
+type fakeConnector struct {
+	d fakeDriver
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return c.d
+}
+
+// checkedConn 实现 NamedValueChecker，被只嵌入 driver.Conn 的 unwrapConn 包装
+type checkedConn struct {
+	fakeConn
+}
+
+func (c *checkedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	nv.Value = "checked"
+	return nil
+}
+
+type unwrapConn struct {
+	driver.Conn
+}
+
+func (c *unwrapConn) Unwrap() driver.Conn { return c.Conn }
+
+func TestCheckNamedValue(t *testing.T) {
+	nv := driver.NamedValue{Ordinal: 1, Value: []int{1}}
+	c := WrapConn(&unwrapConn{&checkedConn{}}, mapErr).(*Conn)
+	assert.Nil(t, c.CheckNamedValue(&nv))
+	assert.Equal(t, "checked", nv.Value)
+
+	c = WrapConn(&unwrapConn{&fakeConn{}}, mapErr).(*Conn)
+	assert.Equal(t, driver.ErrSkip, c.CheckNamedValue(&nv))
+}
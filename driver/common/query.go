@@ -0,0 +1,21 @@
+package common
+
+import "strings"
+
+// ReplaceBackticks 将 MySQL 风格的反引号标识符替换为双引号标识符，单引号字符串中的反引号保持不变
+func ReplaceBackticks(query string) string {
+	if !strings.ContainsRune(query, '`') {
+		return query
+	}
+	b := []byte(query)
+	inQuote := false
+	for i, c := range b {
+		switch {
+		case c == '\'':
+			inQuote = !inQuote
+		case c == '`' && !inQuote:
+			b[i] = '"'
+		}
+	}
+	return string(b)
+}
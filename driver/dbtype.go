@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrUnsupportedDBType 可用于 errors.Is 判断数据库类型未注册的错误
+var ErrUnsupportedDBType = errors.New("proton-rds: unsupported DB_TYPE")
+
+// UnsupportedDBTypeError 为数据库类型未注册时返回的错误，包含所有已注册的类型
+type UnsupportedDBTypeError struct {
+	DBType    string
+	Supported []string
+}
+
+func (e *UnsupportedDBTypeError) Error() string {
+	return fmt.Sprintf("proton-rds: unsupported DB_TYPE %q, supported types: %s",
+		e.DBType, strings.Join(e.Supported, ", "))
+}
+
+func (e *UnsupportedDBTypeError) Is(target error) bool {
+	return target == ErrUnsupportedDBType
+}
+
+// dbTypeAliases 为常见写法到数据库类型名的映射
+var dbTypeAliases = map[string]string{
+	"KINGBASE":   "KDB9",
+	"KINGBASEES": "KDB9",
+	"KES":        "KDB9",
+	"KDB":        "KDB9",
+	"DAMENG":     "DM8",
+	"DM":         "DM8",
+	"POSTGRESQL": "POSTGRES",
+	"PG":         "POSTGRES",
+	"OB":         "OCEANBASE",
+	"SQLITE3":    "SQLITE",
+}
+
+// NormalizeDBType 将数据库类型转换为大写的注册名，并处理别名，
+// 如 kingbase/kes/kdb 转为 KDB9，dameng/dm 转为 DM8
+func NormalizeDBType(dbType string) string {
+	dbType = strings.ToUpper(strings.TrimSpace(dbType))
+	if v, ok := dbTypeAliases[dbType]; ok {
+		return v
+	}
+	return dbType
+}
+
+var lenientDBType atomic.Bool
+
+// SetLenientDBType 设置是否开启宽松模式。开启后未注册的数据库类型退回到 MySQL 驱动，
+// 不开启时返回 *UnsupportedDBTypeError。也可通过环境变量 RDS_SDK_LENIENT_DB_TYPE=1 开启
+func SetLenientDBType(enable bool) {
+	lenientDBType.Store(enable)
+}
+
+func lenient() bool {
+	return lenientDBType.Load() || os.Getenv("RDS_SDK_LENIENT_DB_TYPE") == "1"
+}
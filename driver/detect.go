@@ -0,0 +1,368 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
+)
+
+// AutoDBType 为自动识别数据库类型时 DB_TYPE 或 dbtype 参数的取值
+const AutoDBType = "AUTO"
+
+var (
+	// probeDialTimeout 为探测连接的建立超时
+	probeDialTimeout = 5 * time.Second
+	// probeReadTimeout 为等待服务端报文的时间，MySQL 建立连接后立即发送握手报文，
+	// Kingbase/PostgreSQL 收到 SSLRequest 后立即回复一个字节，DM 收到 STARTUP 消息后立即回复消息头
+	probeReadTimeout = 2 * time.Second
+)
+
+var (
+	errUndetectableDBType = errors.New("proton-rds: cannot detect DB_TYPE from server handshake")
+	errConnectorClosed    = errors.New("proton-rds: connector is closed")
+)
+
+// DM 消息头为 64 字节，小端序，依次为 4 字节语句句柄、2 字节命令号、4 字节消息体长度，第 20 字节为前 20 字节的异或校验
+const (
+	dmHeaderLen    = 64
+	dmCmdOffset    = 4
+	dmLenOffset    = 6
+	dmCheckOffset  = 20
+	dmCmdStartup   = 200
+	dmMaxReplySize = 1 << 20
+)
+
+// DetectDBType 连接 addr 并根据服务端的握手报文判断数据库类型：
+//   - 服务端先发送协议版本为 10 的初始握手报文的为 MySQL，版本串包含 TiDB、MariaDB、OceanBase 时分别为 TIDB、MARIADB、OCEANBASE
+//   - 对 SSLRequest 回复 'S'、'N' 或错误报文的为 Kingbase（KDB9），PostgreSQL 和 openGauss 的回复与 Kingbase 相同，
+//     只凭地址无法区分，通过 DSN 自动识别时会再建立一个连接按 version() 区分
+//   - 不回复 SSLRequest 时另建一个连接发送 DM 的 STARTUP 消息，回复 STARTUP 消息头的为 DM8
+//
+// 服务端对三种报文均无回复（如防火墙丢弃、只接受 TLS 的代理）时返回错误，不猜测数据库类型
+func DetectDBType(ctx context.Context, addr string) (string, error) {
+	c, err := dialProbe(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	buf := make([]byte, 256)
+	n, err := probe(c, nil, buf, 5)
+	if err == nil {
+		return detectFromGreeting(buf[:n])
+	}
+	if !isTimeout(err) {
+		return "", fmt.Errorf("%w: %v", errUndetectableDBType, err)
+	}
+
+	sslRequest := make([]byte, 8)
+	binary.BigEndian.PutUint32(sslRequest[0:4], 8)
+	binary.BigEndian.PutUint32(sslRequest[4:8], 80877103)
+	_, err = probe(c, sslRequest, buf, 1)
+	switch {
+	case err == nil && (buf[0] == 'S' || buf[0] == 'N' || buf[0] == 'E'):
+		return "KDB9", nil
+	case err == nil:
+		return "", fmt.Errorf("%w: unexpected response %q", errUndetectableDBType, buf[0])
+	case !isTimeout(err):
+		return "", fmt.Errorf("%w: %v", errUndetectableDBType, err)
+	}
+	// DM 服务端等待完整的消息头，已写入的 SSLRequest 会被当作消息头的一部分，需另建连接
+	return detectDM(ctx, addr)
+}
+
+// detectDM 发送只有消息头的 DM STARTUP 消息，服务端回复命令号为 STARTUP 的消息头时为 DM8
+func detectDM(ctx context.Context, addr string) (string, error) {
+	c, err := dialProbe(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	buf := make([]byte, dmHeaderLen)
+	if _, err = probe(c, dmStartup(), buf, dmHeaderLen); err != nil {
+		if isTimeout(err) {
+			return "", fmt.Errorf("%w: no response to MySQL, PostgreSQL or DM handshake", errUndetectableDBType)
+		}
+		return "", fmt.Errorf("%w: %v", errUndetectableDBType, err)
+	}
+	cmd := binary.LittleEndian.Uint16(buf[dmCmdOffset:])
+	size := binary.LittleEndian.Uint32(buf[dmLenOffset:])
+	if cmd != dmCmdStartup || size > dmMaxReplySize {
+		return "", fmt.Errorf("%w: unexpected DM reply command %d, length %d", errUndetectableDBType, cmd, size)
+	}
+	return "DM8", nil
+}
+
+// dmStartup 返回消息体为空的 DM STARTUP 消息
+func dmStartup() []byte {
+	p := make([]byte, dmHeaderLen)
+	binary.LittleEndian.PutUint16(p[dmCmdOffset:], dmCmdStartup)
+	var check byte
+	for _, b := range p[:dmCheckOffset] {
+		check ^= b
+	}
+	p[dmCheckOffset] = check
+	return p
+}
+
+func dialProbe(ctx context.Context, addr string) (net.Conn, error) {
+	d := net.Dialer{Timeout: probeDialTimeout}
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// probe 写入 req 后读取至少 n 个字节的回复，req 为空时只读取
+func probe(c net.Conn, req, buf []byte, n int) (int, error) {
+	if len(req) > 0 {
+		c.SetWriteDeadline(time.Now().Add(probeReadTimeout))
+		if _, err := c.Write(req); err != nil {
+			return 0, err
+		}
+	}
+	c.SetReadDeadline(time.Now().Add(probeReadTimeout))
+	return io.ReadAtLeast(c, buf, n)
+}
+
+// detectFromGreeting 解析 MySQL 初始握手报文：3 字节长度、1 字节序号、协议版本、以 0 结尾的版本串
+func detectFromGreeting(p []byte) (string, error) {
+	if p[3] != 0 {
+		return "", fmt.Errorf("%w: unexpected packet sequence %d", errUndetectableDBType, p[3])
+	}
+	switch p[4] {
+	case 10:
+	case 0xff:
+		// 服务端拒绝连接时（如 host is not allowed）直接返回错误报文
+		return "MYSQL", nil
+	default:
+		return "", fmt.Errorf("%w: unexpected protocol version %d", errUndetectableDBType, p[4])
+	}
+	version := p[5:]
+	if i := bytes.IndexByte(version, 0); i >= 0 {
+		version = version[:i]
+	}
+	v := strings.ToLower(string(version))
+	switch {
+	case strings.Contains(v, "tidb"):
+		return "TIDB", nil
+	case strings.Contains(v, "mariadb"):
+		return "MARIADB", nil
+	case strings.Contains(v, "oceanbase"):
+		return "OCEANBASE", nil
+	}
+	return "MYSQL", nil
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout() || errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// probeAddr 从 DSN 中取出探测地址，多个主机时探测第一个
+func probeAddr(dsn string) (string, error) {
+	cfg, err := common.ParseMySQLDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Net != "" && !strings.HasPrefix(cfg.Net, "tcp") {
+		return "", fmt.Errorf("%w: unsupported network %q", errUndetectableDBType, cfg.Net)
+	}
+	addr := cfg.Addr
+	// 多主机格式为 ip1,ip2:port 或 [ip1,ip2]:port
+	if i := strings.IndexByte(addr, ','); i >= 0 {
+		port := addr[strings.LastIndexByte(addr, ':')+1:]
+		addr = net.JoinHostPort(strings.TrimPrefix(addr[:i], "["), port)
+	}
+	return addr, nil
+}
+
+// detectDSN 探测 DSN 对应地址上的数据库类型，握手报文为 Kingbase/PostgreSQL/openGauss 时再按 version() 区分
+func detectDSN(ctx context.Context, dsn string) (string, error) {
+	addr, err := probeAddr(dsn)
+	if err != nil {
+		return "", err
+	}
+	dbType, err := DetectDBType(ctx, addr)
+	if err != nil || dbType != "KDB9" {
+		return dbType, err
+	}
+	return detectPGFamily(ctx, dsn)
+}
+
+// detectPGFamily 用 POSTGRES 数据库类型建立一个连接，按 version() 区分 Kingbase、PostgreSQL 和 openGauss，
+// 该连接指定了 database_mode，不依赖 Kingbase 特有的 show database_mode
+func detectPGFamily(ctx context.Context, dsn string) (string, error) {
+	b, ok := LookupBackend("POSTGRES")
+	if !ok {
+		return "KDB9", nil
+	}
+	connector, err := b.OpenConnector(dsn)
+	if err != nil {
+		return "", err
+	}
+	if closer, ok := connector.(io.Closer); ok {
+		defer closer.Close()
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	q, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return "KDB9", nil
+	}
+	rows, err := q.QueryContext(ctx, "SELECT version()", nil)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, len(rows.Columns()))
+	if err = rows.Next(dest); err != nil {
+		return "", err
+	}
+	return pgFamilyType(fmt.Sprintf("%s", dest[0])), nil
+}
+
+// pgFamilyType 按 version() 的返回值判断数据库类型，如 KingbaseES V008R006C008B0014 on x86_64、
+// (openGauss 5.0.0 build a07d57c3) compiled at ...、PostgreSQL 15.4 on x86_64-pc-linux-gnu
+func pgFamilyType(version string) string {
+	v := strings.ToLower(version)
+	switch {
+	case strings.Contains(v, "kingbase"):
+		return "KDB9"
+	case strings.Contains(v, "opengauss"):
+		return "OPENGAUSS"
+	}
+	return "POSTGRES"
+}
+
+// detectBackend 返回 DSN 对应地址上的数据库类型，每次调用都重新探测，连接器只在建立第一个连接时探测
+func detectBackend(ctx context.Context, dsn string) (Backend, error) {
+	dbType, err := detectDSN(ctx, dsn)
+	if err != nil {
+		return Backend{}, err
+	}
+	b, ok := LookupBackend(dbType)
+	if !ok {
+		return Backend{}, &UnsupportedDBTypeError{DBType: dbType, Supported: Backends()}
+	}
+	return b, nil
+}
+
+// autoConnector 在第一次建立连接时探测数据库类型，之后复用探测到的连接器。
+// 探测失败不缓存；建立连接时连接丢失会丢弃探测结果，地址被指向其它数据库（如故障转移的 VIP）后重新探测
+type autoConnector struct {
+	dsn string
+
+	mu        sync.Mutex
+	dbType    string
+	connector driver.Connector
+	closed    bool
+	pool      poolCache
+}
+
+func (c *autoConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := c.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := wrapConn(connector.Connect(ctx))
+	if cat := Classify(err); cat == ConnectionLost || cat == Timeout {
+		c.forget(connector)
+	}
+	return conn, err
+}
+
+// Driver 返回绑定探测结果的 RDSDriver，尚未探测时绑定 AUTO
+func (c *autoConnector) Driver() driver.Driver {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dbType == "" {
+		return RDSDriver{dbType: AutoDBType}
+	}
+	return RDSDriver{dbType: c.dbType, pool: &c.pool}
+}
+
+// resolve 返回探测到的连接器，尚未探测时在锁外探测，并发探测时保留先完成的结果，关闭后返回错误
+func (c *autoConnector) resolve(ctx context.Context) (driver.Connector, error) {
+	c.mu.Lock()
+	connector, closed := c.connector, c.closed
+	c.mu.Unlock()
+	if closed {
+		return nil, errConnectorClosed
+	}
+	if connector != nil {
+		return connector, nil
+	}
+
+	dbType, err := detectDSN(ctx, c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := LookupBackend(dbType)
+	if !ok {
+		return nil, &UnsupportedDBTypeError{DBType: dbType, Supported: Backends()}
+	}
+	if connector, err = b.OpenConnector(c.dsn); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		closeConnector(connector)
+		return nil, errConnectorClosed
+	}
+	if c.connector != nil {
+		// 并发探测时保留先完成的结果，关闭后完成的连接器
+		winner := c.connector
+		c.mu.Unlock()
+		closeConnector(connector)
+		return winner, nil
+	}
+	c.dbType, c.connector = dbType, connector
+	c.mu.Unlock()
+	return connector, nil
+}
+
+// forget 丢弃并关闭 connector 对应的探测结果，清空连接池缓存，下次建立连接时重新探测。
+// 已由该连接器建立的连接不受影响，关闭连接器只释放其持有的资源
+func (c *autoConnector) forget(connector driver.Connector) {
+	c.mu.Lock()
+	if c.connector != connector {
+		c.mu.Unlock()
+		return
+	}
+	c.dbType, c.connector = "", nil
+	c.mu.Unlock()
+	c.pool.reset()
+	closeConnector(connector)
+}
+
+// Close 关闭探测到的连接器，sql.DB.Close 时调用，关闭后不再建立连接
+func (c *autoConnector) Close() error {
+	c.mu.Lock()
+	connector := c.connector
+	c.dbType, c.connector, c.closed = "", nil, true
+	c.mu.Unlock()
+	return closeConnector(connector)
+}
+
+// closeConnector 关闭实现了 io.Closer 的连接器
+func closeConnector(connector driver.Connector) error {
+	if closer, ok := connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer 在本地监听并用 handle 处理每个连接，返回监听地址
+func fakeServer(t *testing.T, handle func(c net.Conn)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				handle(c)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func mysqlGreeting(version string) []byte {
+	payload := append([]byte{10}, version...)
+	payload = append(payload, 0, 1, 0, 0, 0)
+	return append([]byte{byte(len(payload)), 0, 0, 0}, payload...)
+}
+
+func TestDetectDBType(t *testing.T) {
+	defer func(d time.Duration) { probeReadTimeout = d }(probeReadTimeout)
+	probeReadTimeout = 200 * time.Millisecond
+
+	tests := []struct {
+		name   string
+		handle func(c net.Conn)
+		want   string
+	}{
+		{
+			name:   "mysql",
+			handle: func(c net.Conn) { c.Write(mysqlGreeting("8.0.33")) },
+			want:   "MYSQL",
+		},
+		{
+			name:   "tidb",
+			handle: func(c net.Conn) { c.Write(mysqlGreeting("5.7.25-TiDB-v7.1.0")) },
+			want:   "TIDB",
+		},
+		{
+			name:   "mariadb",
+			handle: func(c net.Conn) { c.Write(mysqlGreeting("5.5.5-10.6.12-MariaDB")) },
+			want:   "MARIADB",
+		},
+		{
+			name:   "oceanbase",
+			handle: func(c net.Conn) { c.Write(mysqlGreeting("5.7.25-OceanBase_CE-v4.2.1.0")) },
+			want:   "OCEANBASE",
+		},
+		{
+			name: "kingbase",
+			handle: func(c net.Conn) {
+				io.ReadFull(c, make([]byte, 8))
+				c.Write([]byte{'N'})
+			},
+			want: "KDB9",
+		},
+		{
+			name:   "dm",
+			handle: dmServer,
+			want:   "DM8",
+		},
+		{
+			// 不回复任何握手报文的服务端不能被当作 DM8
+			name: "silent",
+			handle: func(c net.Conn) {
+				io.Copy(io.Discard, c)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := fakeServer(t, tt.handle)
+			got, err := DetectDBType(context.Background(), addr)
+			if tt.want == "" {
+				assert.ErrorIs(t, err, errUndetectableDBType)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// dmServer 等待完整的 64 字节消息头，收到 STARTUP 消息时回复 STARTUP 消息头
+func dmServer(c net.Conn) {
+	head := make([]byte, dmHeaderLen)
+	if _, err := io.ReadFull(c, head); err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint16(head[dmCmdOffset:]) != dmCmdStartup {
+		return
+	}
+	reply := make([]byte, dmHeaderLen)
+	binary.LittleEndian.PutUint16(reply[dmCmdOffset:], dmCmdStartup)
+	c.Write(reply)
+}
+
+func TestProbeAddr(t *testing.T) {
+	for dsn, want := range map[string]string{
+		"user:pwd@tcp(localhost:5236)/test?timeout=10s": "localhost:5236",
+		"user:pwd@tcp(10.0.0.1,10.0.0.2:5236)/test":     "10.0.0.1:5236",
+		"user:pwd@tcp([::1,::2]:5236)/test":             "[::1]:5236",
+	} {
+		addr, err := probeAddr(dsn)
+		assert.Nil(t, err)
+		assert.Equal(t, want, addr, dsn)
+	}
+}
+
+func TestAutoConnector(t *testing.T) {
+	var probes atomic.Int32
+	addr := fakeServer(t, func(c net.Conn) {
+		probes.Add(1)
+		c.Write(mysqlGreeting("8.0.33"))
+	})
+
+	connector, err := RDSDriver{}.OpenConnector("user:pwd@tcp(" + addr + ")/test?dbtype=auto")
+	assert.Nil(t, err)
+	auto, ok := connector.(*autoConnector)
+	assert.True(t, ok)
+
+	_, err = auto.resolve(context.Background())
+	assert.Nil(t, err)
+	_, err = auto.resolve(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "MYSQL", auto.dbType)
+	assert.Equal(t, int32(1), probes.Load())
+}
+
+func TestAutoConnectorRetry(t *testing.T) {
+	defer func(d time.Duration) { probeReadTimeout = d }(probeReadTimeout)
+	probeReadTimeout = 200 * time.Millisecond
+
+	// 奇数次连接时服务端直接断开，第一次探测失败不缓存
+	var probes atomic.Int32
+	addr := fakeServer(t, func(c net.Conn) {
+		if probes.Add(1)%2 == 1 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+		c.Write(mysqlGreeting("8.0.33"))
+	})
+	auto := &autoConnector{dsn: "user:pwd@tcp(" + addr + ")/test"}
+	_, err := auto.resolve(context.Background())
+	assert.NotNil(t, err)
+
+	// 探测在锁外进行，不阻塞 Driver
+	done := make(chan error)
+	go func() {
+		_, err := auto.resolve(context.Background())
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	assert.Equal(t, RDSDriver{dbType: AutoDBType}, auto.Driver())
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Nil(t, <-done)
+	assert.Equal(t, "MYSQL", auto.dbType)
+
+	// 建立连接时服务端断开，丢弃探测结果
+	_, err = auto.Connect(context.Background())
+	assert.Equal(t, ConnectionLost, Classify(err))
+	assert.Nil(t, auto.connector)
+	_, err = auto.resolve(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), probes.Load())
+}
+
+type closingConnector struct {
+	closed bool
+}
+
+func (c *closingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return nil, driver.ErrBadConn
+}
+
+func (c *closingConnector) Driver() driver.Driver {
+	return RDSDriver{}
+}
+
+func (c *closingConnector) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAutoConnectorClose(t *testing.T) {
+	// 丢弃探测结果时关闭对应的连接器
+	first := &closingConnector{}
+	auto := &autoConnector{dbType: "MYSQL", connector: first}
+	auto.forget(&closingConnector{})
+	assert.False(t, first.closed)
+	auto.forget(first)
+	assert.True(t, first.closed)
+	assert.Nil(t, auto.connector)
+
+	// sql.DB.Close 关闭探测到的连接器，之后不再探测
+	second := &closingConnector{}
+	auto = &autoConnector{dbType: "MYSQL", connector: second}
+	db := sql.OpenDB(auto)
+	assert.Nil(t, db.Close())
+	assert.True(t, second.closed)
+	_, err := auto.resolve(context.Background())
+	assert.ErrorIs(t, err, errConnectorClosed)
+}
+
+func TestPGFamilyType(t *testing.T) {
+	for version, want := range map[string]string{
+		"KingbaseES V008R006C008B0014 on x86_64-pc-linux-gnu, compiled by gcc": "KDB9",
+		"(openGauss 5.0.0 build a07d57c3) compiled at 2023-03-29 03:07:56":     "OPENGAUSS",
+		"PostgreSQL 15.4 on x86_64-pc-linux-gnu, compiled by gcc":              "POSTGRES",
+	} {
+		assert.Equal(t, want, pgFamilyType(version), version)
+	}
+}
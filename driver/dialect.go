@@ -0,0 +1,434 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb"
+)
+
+// Dialect 描述一种数据库类型的 SQL 写法差异，业务代码通过 DialectOf 获取当前连接的方言，
+// 不再按 DB_TYPE 写 switch 分支
+type Dialect interface {
+	// Name 返回数据库类型名，如 MYSQL、DM8、KDB9
+	Name() string
+	// QuoteIdentifier 引用表名、列名等标识符，schema.table 形式的名字按 . 分段引用
+	QuoteIdentifier(name string) string
+	// QuoteLiteral 将字符串转换为 SQL 字符串字面量，用于不支持参数的 DDL 等语句
+	QuoteLiteral(literal string) string
+	// Placeholder 返回第 n 个参数（从 1 开始）的原生占位符，各驱动都接受 ?，此处为服务端的原生写法
+	Placeholder(n int) string
+	// Paginate 返回追加在 ORDER BY 之后的分页子句，limit 小于等于 0 时不限制行数
+	Paginate(limit, offset int64) string
+	// BoolLiteral 返回布尔值的字面量
+	BoolLiteral(b bool) string
+	// CurrentTimestamp 返回当前时间的表达式
+	CurrentTimestamp() string
+	// Upsert 返回插入 rows 行数据、按 keys 列判断冲突并更新其余列的语句，
+	// 参数按行依次排列，每行按 columns 的顺序，columns 全部为 keys 时冲突的行保持不变
+	Upsert(table string, columns, keys []string, rows int) string
+}
+
+var dialects = struct {
+	sync.RWMutex
+	m map[string]Dialect
+}{m: map[string]Dialect{
+	"MYSQL":     mysqlDialect{name: "MYSQL"},
+	"MARIADB":   mysqlDialect{name: "MARIADB"},
+	"GOLDENDB":  mysqlDialect{name: "GOLDENDB"},
+	"TIDB":      mysqlDialect{name: "TIDB"},
+	"OCEANBASE": mysqlDialect{name: "OCEANBASE"},
+	"DM8":       dmDialect{},
+	"KDB9":      kingbaseDialect{},
+	"POSTGRES":  postgresDialect{name: "POSTGRES"},
+	"OPENGAUSS": postgresDialect{name: "OPENGAUSS"},
+	"SQLITE":    sqliteDialect{},
+}}
+
+// RegisterDialect 为数据库类型注册方言，已存在时覆盖，一般与 RegisterBackend 一起调用
+func RegisterDialect(dbType string, d Dialect) {
+	dialects.Lock()
+	defer dialects.Unlock()
+	dialects.m[NormalizeDBType(dbType)] = d
+}
+
+// DialectFor 返回数据库类型的方言，KDB9 按未知兼容模式处理，只使用各模式通用的写法
+func DialectFor(dbType string) (Dialect, bool) {
+	dialects.RLock()
+	defer dialects.RUnlock()
+	d, ok := dialects.m[NormalizeDBType(dbType)]
+	return d, ok
+}
+
+// KingbaseDialect 返回 Kingbase 指定兼容模式（pg、oracle、mysql、sqlserver）的方言
+func KingbaseDialect(mode string) Dialect {
+	return kingbaseDialect{mode: strings.ToLower(mode)}
+}
+
+// DBTypeOf 返回通过 proton-rds 驱动打开的连接池使用的数据库类型，其它驱动返回空字符串
+// 数据库类型为 auto 且尚未建立连接时返回 AUTO
+func DBTypeOf(db *sql.DB) string {
+	switch d := db.Driver().(type) {
+	case RDSDriver:
+		return d.dbType
+	case *RDSDriver:
+		return d.dbType
+	}
+	return ""
+}
+
+// DialectOf 返回连接池当前使用的方言，Kingbase 按连接的 database_mode 返回对应模式的方言
+func DialectOf(ctx context.Context, db *sql.DB) (Dialect, error) {
+	dbType := DBTypeOf(db)
+	if dbType != AutoDBType && dbType != "KDB9" {
+		return dialectFor(dbType)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if dbType = DBTypeOf(db); dbType != "KDB9" {
+		return dialectFor(dbType)
+	}
+	var mode string
+	err = conn.Raw(func(dc any) error {
+		dc = UnwrapConn(dc)
+		if m, ok := dc.(interface{ DatabaseMode() string }); ok {
+			mode = m.DatabaseMode()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return KingbaseDialect(mode), nil
+}
+
+func dialectFor(dbType string) (Dialect, error) {
+	if d, ok := DialectFor(dbType); ok {
+		return d, nil
+	}
+	return nil, &UnsupportedDBTypeError{DBType: dbType, Supported: Backends()}
+}
+
+// quoteIdentifier 按 . 分段，用 quote 包围每一段，段内的 quote 字符写两次
+func quoteIdentifier(name string, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// values 返回 rows 行、每行 n 个参数的 VALUES 子句内容
+func values(d Dialect, n, rows int) string {
+	var b strings.Builder
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for c := 0; c < n; c++ {
+			if c > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(d.Placeholder(r*n + c + 1))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// insertInto 返回 INSERT INTO table (columns) VALUES ... 部分
+func insertInto(d Dialect, table string, columns []string, rows int) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		d.QuoteIdentifier(table), quoteColumns(d, columns, ""), values(d, len(columns), rows))
+}
+
+// quoteColumns 引用列名并用逗号连接，prefix 不为空时加在每个列名前
+func quoteColumns(d Dialect, columns []string, prefix string) string {
+	s := make([]string, len(columns))
+	for i, c := range columns {
+		s[i] = prefix + d.QuoteIdentifier(c)
+	}
+	return strings.Join(s, ", ")
+}
+
+// updateColumns 返回 columns 中不属于 keys 的列
+func updateColumns(columns, keys []string) []string {
+	var updates []string
+	for _, c := range columns {
+		isKey := false
+		for _, k := range keys {
+			if strings.EqualFold(c, k) {
+				isKey = true
+				break
+			}
+		}
+		if !isKey {
+			updates = append(updates, c)
+		}
+	}
+	return updates
+}
+
+// assignments 返回 col = format(col) 形式的赋值列表，format 中的 %s 为引用后的列名
+func assignments(d Dialect, columns []string, format string) string {
+	s := make([]string, len(columns))
+	for i, c := range columns {
+		q := d.QuoteIdentifier(c)
+		s[i] = q + " = " + fmt.Sprintf(format, q)
+	}
+	return strings.Join(s, ", ")
+}
+
+// mysqlDialect 为 MySQL 及兼容 MySQL 协议的数据库的方言
+type mysqlDialect struct {
+	name string
+}
+
+func (d mysqlDialect) Name() string { return d.name }
+
+func (mysqlDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, "`") }
+
+func (mysqlDialect) QuoteLiteral(literal string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `''`, "\x00", `\0`)
+	return "'" + r.Replace(literal) + "'"
+}
+
+func (mysqlDialect) Placeholder(int) string { return "?" }
+
+func (mysqlDialect) Paginate(limit, offset int64) string {
+	if limit <= 0 {
+		if offset <= 0 {
+			return ""
+		}
+		// MySQL 不支持单独的 OFFSET，官方文档建议使用最大值
+		return "LIMIT 18446744073709551615 OFFSET " + strconv.FormatInt(offset, 10)
+	}
+	if offset <= 0 {
+		return "LIMIT " + strconv.FormatInt(limit, 10)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (mysqlDialect) BoolLiteral(b bool) string { return boolLiteral(b, "TRUE", "FALSE") }
+
+func (mysqlDialect) CurrentTimestamp() string { return "CURRENT_TIMESTAMP" }
+
+func (d mysqlDialect) Upsert(table string, columns, keys []string, rows int) string {
+	return upsertOnDuplicateKey(d, table, columns, keys, rows, "VALUES(%s)")
+}
+
+// upsertOnDuplicateKey 生成 INSERT ... ON DUPLICATE KEY UPDATE，没有可更新的列时将第一个键列赋值为自身
+func upsertOnDuplicateKey(d Dialect, table string, columns, keys []string, rows int, format string) string {
+	updates := updateColumns(columns, keys)
+	set := assignments(d, updates, format)
+	if len(updates) == 0 {
+		set = assignments(d, columns[:1], "%s")
+	}
+	return insertInto(d, table, columns, rows) + " ON DUPLICATE KEY UPDATE " + set
+}
+
+// upsertOnConflict 生成 INSERT ... ON CONFLICT (keys) DO UPDATE
+func upsertOnConflict(d Dialect, table string, columns, keys []string, rows int) string {
+	s := insertInto(d, table, columns, rows) + " ON CONFLICT (" + quoteColumns(d, keys, "") + ")"
+	updates := updateColumns(columns, keys)
+	if len(updates) == 0 {
+		return s + " DO NOTHING"
+	}
+	return s + " DO UPDATE SET " + assignments(d, updates, "EXCLUDED.%s")
+}
+
+// paginateFetch 生成 SQL 标准的 OFFSET ... ROWS FETCH NEXT ... ROWS ONLY
+func paginateFetch(limit, offset int64) string {
+	var s []string
+	if offset > 0 || limit > 0 {
+		s = append(s, fmt.Sprintf("OFFSET %d ROWS", max(offset, 0)))
+	}
+	if limit > 0 {
+		s = append(s, fmt.Sprintf("FETCH NEXT %d ROWS ONLY", limit))
+	}
+	return strings.Join(s, " ")
+}
+
+func boolLiteral(b bool, t, f string) string {
+	if b {
+		return t
+	}
+	return f
+}
+
+// dmDialect 为达梦数据库的方言，分页使用各兼容模式都支持的 OFFSET/FETCH 写法
+type dmDialect struct{}
+
+func (dmDialect) Name() string { return "DM8" }
+
+func (dmDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`) }
+
+func (dmDialect) QuoteLiteral(literal string) string {
+	return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+}
+
+func (dmDialect) Placeholder(int) string { return "?" }
+
+func (dmDialect) Paginate(limit, offset int64) string { return paginateFetch(limit, offset) }
+
+func (dmDialect) BoolLiteral(b bool) string { return boolLiteral(b, "1", "0") }
+
+func (dmDialect) CurrentTimestamp() string { return "SYSDATE" }
+
+// Upsert 生成 MERGE INTO，多行数据通过 SELECT ... FROM DUAL UNION ALL 作为源表
+func (d dmDialect) Upsert(table string, columns, keys []string, rows int) string {
+	var src strings.Builder
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			src.WriteString(" UNION ALL ")
+		}
+		src.WriteString("SELECT ")
+		for c, col := range columns {
+			if c > 0 {
+				src.WriteString(", ")
+			}
+			src.WriteString(d.Placeholder(r*len(columns) + c + 1))
+			if r == 0 {
+				src.WriteString(" AS " + d.QuoteIdentifier(col))
+			}
+		}
+		src.WriteString(" FROM DUAL")
+	}
+	on := make([]string, len(keys))
+	for i, k := range keys {
+		q := d.QuoteIdentifier(k)
+		on[i] = "T." + q + " = S." + q
+	}
+	s := fmt.Sprintf("MERGE INTO %s T USING (%s) S ON (%s)",
+		d.QuoteIdentifier(table), src.String(), strings.Join(on, " AND "))
+	if updates := updateColumns(columns, keys); len(updates) > 0 {
+		set := make([]string, len(updates))
+		for i, u := range updates {
+			q := d.QuoteIdentifier(u)
+			set[i] = "T." + q + " = S." + q
+		}
+		s += " WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ", ")
+	}
+	return s + fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+		quoteColumns(d, columns, ""), quoteColumns(d, columns, "S."))
+}
+
+// kingbaseDialect 为 Kingbase 的方言，mode 为连接的 database_mode，mysql 模式使用 MySQL 的写法，
+// 其它模式和未知模式使用 PostgreSQL 的写法，分页使用 oracle 模式也支持的 OFFSET/FETCH
+type kingbaseDialect struct {
+	mode string
+}
+
+func (kingbaseDialect) Name() string { return "KDB9" }
+
+func (d kingbaseDialect) QuoteIdentifier(name string) string {
+	if d.mode == "mysql" {
+		return quoteIdentifier(name, "`")
+	}
+	return quoteIdentifier(name, `"`)
+}
+
+func (d kingbaseDialect) QuoteLiteral(literal string) string {
+	return strings.TrimSpace(gokb.QuoteLiteral(literal))
+}
+
+func (kingbaseDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (d kingbaseDialect) Paginate(limit, offset int64) string {
+	if d.mode == "mysql" {
+		return mysqlDialect{}.Paginate(limit, offset)
+	}
+	return paginateFetch(limit, offset)
+}
+
+func (kingbaseDialect) BoolLiteral(b bool) string { return boolLiteral(b, "TRUE", "FALSE") }
+
+func (kingbaseDialect) CurrentTimestamp() string { return "CURRENT_TIMESTAMP" }
+
+func (d kingbaseDialect) Upsert(table string, columns, keys []string, rows int) string {
+	if d.mode == "mysql" {
+		return upsertOnDuplicateKey(d, table, columns, keys, rows, "VALUES(%s)")
+	}
+	return upsertOnConflict(d, table, columns, keys, rows)
+}
+
+// postgresDialect 为 PostgreSQL 和 openGauss 的方言，openGauss 不支持 ON CONFLICT，使用 ON DUPLICATE KEY UPDATE
+type postgresDialect struct {
+	name string
+}
+
+func (d postgresDialect) Name() string { return d.name }
+
+func (postgresDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`) }
+
+func (postgresDialect) QuoteLiteral(literal string) string {
+	return strings.TrimSpace(gokb.QuoteLiteral(literal))
+}
+
+func (postgresDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (postgresDialect) Paginate(limit, offset int64) string { return paginateLimit(limit, offset) }
+
+func (postgresDialect) BoolLiteral(b bool) string { return boolLiteral(b, "TRUE", "FALSE") }
+
+func (postgresDialect) CurrentTimestamp() string { return "CURRENT_TIMESTAMP" }
+
+func (d postgresDialect) Upsert(table string, columns, keys []string, rows int) string {
+	if d.name == "OPENGAUSS" {
+		if len(updateColumns(columns, keys)) == 0 {
+			return insertInto(d, table, columns, rows) + " ON DUPLICATE KEY UPDATE NOTHING"
+		}
+		return upsertOnDuplicateKey(d, table, columns, keys, rows, "EXCLUDED.%s")
+	}
+	return upsertOnConflict(d, table, columns, keys, rows)
+}
+
+// paginateLimit 生成 LIMIT ... OFFSET ...，不限制行数时省略 LIMIT
+func paginateLimit(limit, offset int64) string {
+	var s []string
+	if limit > 0 {
+		s = append(s, "LIMIT "+strconv.FormatInt(limit, 10))
+	}
+	if offset > 0 {
+		s = append(s, "OFFSET "+strconv.FormatInt(offset, 10))
+	}
+	return strings.Join(s, " ")
+}
+
+// sqliteDialect 为 SQLite 的方言
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "SQLITE" }
+
+func (sqliteDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`) }
+
+func (sqliteDialect) QuoteLiteral(literal string) string {
+	return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+}
+
+func (sqliteDialect) Placeholder(int) string { return "?" }
+
+// Paginate 中 SQLite 的 OFFSET 必须跟在 LIMIT 之后，不限制行数时使用 LIMIT -1
+func (sqliteDialect) Paginate(limit, offset int64) string {
+	if limit <= 0 && offset > 0 {
+		return "LIMIT -1 OFFSET " + strconv.FormatInt(offset, 10)
+	}
+	return paginateLimit(limit, offset)
+}
+
+func (sqliteDialect) BoolLiteral(b bool) string { return boolLiteral(b, "1", "0") }
+
+func (sqliteDialect) CurrentTimestamp() string { return "CURRENT_TIMESTAMP" }
+
+func (d sqliteDialect) Upsert(table string, columns, keys []string, rows int) string {
+	return upsertOnConflict(d, table, columns, keys, rows)
+}
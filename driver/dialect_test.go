@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect(t *testing.T) {
+	columns, keys := []string{"f_id", "f_name"}, []string{"f_id"}
+	tests := []struct {
+		dbType   string
+		dialect  Dialect
+		ident    string
+		literal  string
+		paginate string
+		upsert   string
+	}{
+		{
+			dbType:   "MYSQL",
+			ident:    "`db`.`t_user`",
+			literal:  `'it''s \\'`,
+			paginate: "LIMIT 10 OFFSET 20",
+			upsert:   "INSERT INTO `db`.`t_user` (`f_id`, `f_name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `f_name` = VALUES(`f_name`)",
+		},
+		{
+			dbType:   "DM8",
+			ident:    `"db"."t_user"`,
+			literal:  `'it''s \'`,
+			paginate: "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+			upsert: `MERGE INTO "db"."t_user" T USING (SELECT ? AS "f_id", ? AS "f_name" FROM DUAL UNION ALL SELECT ?, ? FROM DUAL) S ON (T."f_id" = S."f_id")` +
+				` WHEN MATCHED THEN UPDATE SET T."f_name" = S."f_name" WHEN NOT MATCHED THEN INSERT ("f_id", "f_name") VALUES (S."f_id", S."f_name")`,
+		},
+		{
+			dbType:   "KDB9",
+			ident:    `"db"."t_user"`,
+			literal:  `E'it''s \\'`,
+			paginate: "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+			upsert:   `INSERT INTO "db"."t_user" ("f_id", "f_name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("f_id") DO UPDATE SET "f_name" = EXCLUDED."f_name"`,
+		},
+		{
+			dbType:   "KDB9",
+			dialect:  KingbaseDialect("MySQL"),
+			ident:    "`db`.`t_user`",
+			literal:  `E'it''s \\'`,
+			paginate: "LIMIT 10 OFFSET 20",
+			upsert:   "INSERT INTO `db`.`t_user` (`f_id`, `f_name`) VALUES ($1, $2), ($3, $4) ON DUPLICATE KEY UPDATE `f_name` = VALUES(`f_name`)",
+		},
+		{
+			dbType:   "OPENGAUSS",
+			ident:    `"db"."t_user"`,
+			literal:  `E'it''s \\'`,
+			paginate: "LIMIT 10 OFFSET 20",
+			upsert:   `INSERT INTO "db"."t_user" ("f_id", "f_name") VALUES ($1, $2), ($3, $4) ON DUPLICATE KEY UPDATE "f_name" = EXCLUDED."f_name"`,
+		},
+		{
+			dbType:   "SQLITE",
+			ident:    `"db"."t_user"`,
+			literal:  `'it''s \'`,
+			paginate: "LIMIT 10 OFFSET 20",
+			upsert:   `INSERT INTO "db"."t_user" ("f_id", "f_name") VALUES (?, ?), (?, ?) ON CONFLICT ("f_id") DO UPDATE SET "f_name" = EXCLUDED."f_name"`,
+		},
+	}
+	for _, tt := range tests {
+		d := tt.dialect
+		if d == nil {
+			var ok bool
+			d, ok = DialectFor(tt.dbType)
+			assert.True(t, ok, tt.dbType)
+		}
+		t.Run(tt.dbType, func(t *testing.T) {
+			assert.Equal(t, tt.dbType, d.Name())
+			assert.Equal(t, tt.ident, d.QuoteIdentifier("db.t_user"))
+			assert.Equal(t, tt.literal, d.QuoteLiteral(`it's \`))
+			assert.Equal(t, tt.paginate, d.Paginate(10, 20))
+			assert.Equal(t, tt.upsert, d.Upsert("db.t_user", columns, keys, 2))
+		})
+	}
+
+	d, _ := DialectFor("mysql")
+	assert.Equal(t, "LIMIT 18446744073709551615 OFFSET 5", d.Paginate(0, 5))
+	assert.Equal(t, "INSERT INTO `t` (`f_id`) VALUES (?) ON DUPLICATE KEY UPDATE `f_id` = `f_id`", d.Upsert("t", keys, keys, 1))
+	d, _ = DialectFor("dameng")
+	assert.Equal(t, "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", d.Paginate(10, 0))
+	d, _ = DialectFor("sqlite")
+	assert.Equal(t, "LIMIT -1 OFFSET 5", d.Paginate(0, 5))
+	assert.Equal(t, `INSERT INTO "t" ("f_id") VALUES (?) ON CONFLICT ("f_id") DO NOTHING`, d.Upsert("t", keys, keys, 1))
+}
@@ -83,6 +83,14 @@ func (rdsConn *RDSConn) QueryContext(ctx context.Context, query string, args []d
 	return rdsConn.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
 }
 
+// CheckNamedValue 交给 DM 驱动检查参数，使数组绑定的 [][]interface{} 等驱动支持的类型不被 database/sql 拒绝
+func (rdsConn *RDSConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := rdsConn.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
 func (rdsConn *RDSConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
 	query = newDmQuery(query, nil)
 	if os.Getenv("RDS_SDK_DM_DEBUG") == "1" {
@@ -2,6 +2,7 @@ package dmdb
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
 )
 
 type TestDBInfo struct {
@@ -172,3 +175,52 @@ func TestPrepareContext(t *testing.T) {
 		})
 	})
 }
+
+// arrayConn 模拟支持数组绑定的 DM 连接
+type arrayConn struct {
+	driver.Conn
+	args []driver.NamedValue
+}
+
+func (c *arrayConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.args = args
+	return driver.RowsAffected(2), nil
+}
+
+func (c *arrayConn) Close() error {
+	return nil
+}
+
+func (c *arrayConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.([][]interface{}); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type arrayConnector struct {
+	conn driver.Conn
+}
+
+func (c arrayConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return common.WrapConn(&RDSConn{c.conn}, func(err error) error { return err }), nil
+}
+
+func (c arrayConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestArrayBinding(t *testing.T) {
+	conn := &arrayConn{}
+	db := sql.OpenDB(arrayConnector{conn})
+	defer db.Close()
+
+	batch := [][]interface{}{{1, "a"}, {2, "b"}}
+	res, err := db.Exec("INSERT INTO `t` (`a`, `b`) VALUES (?, ?)", batch)
+	assert.Nil(t, err)
+	n, _ := res.RowsAffected()
+	assert.Equal(t, int64(2), n)
+	if assert.Len(t, conn.args, 1) {
+		assert.Equal(t, batch, conn.args[0].Value)
+	}
+}
@@ -0,0 +1,293 @@
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gitee.com/chunanyong/dm"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb"
+)
+
+// Category 为与数据库类型无关的错误类别
+type Category int
+
+const (
+	Unknown Category = iota
+	UniqueViolation
+	ForeignKeyViolation
+	Deadlock
+	LockTimeout
+	SerializationFailure
+	ConnectionLost
+	ReadOnly
+	SyntaxError
+	PermissionDenied
+	Timeout
+)
+
+var categoryNames = [...]string{
+	Unknown:              "Unknown",
+	UniqueViolation:      "UniqueViolation",
+	ForeignKeyViolation:  "ForeignKeyViolation",
+	Deadlock:             "Deadlock",
+	LockTimeout:          "LockTimeout",
+	SerializationFailure: "SerializationFailure",
+	ConnectionLost:       "ConnectionLost",
+	ReadOnly:             "ReadOnly",
+	SyntaxError:          "SyntaxError",
+	PermissionDenied:     "PermissionDenied",
+	Timeout:              "Timeout",
+}
+
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return "Unknown"
+	}
+	return categoryNames[c]
+}
+
+// 各错误类别对应的哨兵错误，SetWrapErrors 开启后通过 proton-rds 驱动执行的语句返回的错误可直接用 errors.Is 判断
+var (
+	ErrUniqueViolation      = errors.New("proton-rds: unique violation")
+	ErrForeignKeyViolation  = errors.New("proton-rds: foreign key violation")
+	ErrDeadlock             = errors.New("proton-rds: deadlock")
+	ErrLockTimeout          = errors.New("proton-rds: lock timeout")
+	ErrSerializationFailure = errors.New("proton-rds: serialization failure")
+	ErrConnectionLost       = errors.New("proton-rds: connection lost")
+	ErrReadOnly             = errors.New("proton-rds: read only")
+	ErrSyntaxError          = errors.New("proton-rds: syntax error")
+	ErrPermissionDenied     = errors.New("proton-rds: permission denied")
+	ErrTimeout              = errors.New("proton-rds: timeout")
+)
+
+var categoryErrors = map[Category]error{
+	UniqueViolation:      ErrUniqueViolation,
+	ForeignKeyViolation:  ErrForeignKeyViolation,
+	Deadlock:             ErrDeadlock,
+	LockTimeout:          ErrLockTimeout,
+	SerializationFailure: ErrSerializationFailure,
+	ConnectionLost:       ErrConnectionLost,
+	ReadOnly:             ErrReadOnly,
+	SyntaxError:          ErrSyntaxError,
+	PermissionDenied:     ErrPermissionDenied,
+	Timeout:              ErrTimeout,
+}
+
+// Error 为已分类的数据库错误，Unwrap 返回具体驱动的原始错误，errors.As 仍可取出 *mysql.MySQLError 等类型
+type Error struct {
+	Category Category
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrUniqueViolation) 等判断成立
+func (e *Error) Is(target error) bool {
+	return target != nil && categoryErrors[e.Category] == target
+}
+
+var wrapErrors atomic.Bool
+
+// SetWrapErrors 设置是否包装具体驱动的连接，开启后语句返回的可识别错误转换为 *Error，可通过 errors.Is 判断类别。
+// 默认不开启，错误保持具体驱动的原始类型，sql.Conn.Raw 取得的也是具体驱动的连接。
+// 也可通过环境变量 RDS_SDK_WRAP_ERRORS=1 开启，只对之后建立的连接生效
+func SetWrapErrors(enable bool) {
+	wrapErrors.Store(enable)
+}
+
+func wrapErrorsEnabled() bool {
+	return wrapErrors.Load() || os.Getenv("RDS_SDK_WRAP_ERRORS") == "1"
+}
+
+// Classifier 返回其它驱动的错误的类别，无法识别时返回 Unknown
+type Classifier func(error) Category
+
+var classifiers struct {
+	sync.RWMutex
+	s []Classifier
+}
+
+// RegisterClassifier 注册其它驱动的错误分类，Classify 无法识别的错误依次交给注册的 Classifier，
+// 供 RegisterBackend 注册的数据库类型使用，如 driver/sqlite
+func RegisterClassifier(c Classifier) {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+	classifiers.s = append(classifiers.s, c)
+}
+
+// Classify 返回错误的类别，支持 MySQL 系、DM8、Kingbase/PostgreSQL 和 RegisterClassifier 注册的错误，
+// 无法识别时返回 Unknown。Classify 直接识别具体驱动的原始错误，不依赖 SetWrapErrors
+func Classify(err error) Category {
+	if err == nil {
+		return Unknown
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Category
+	}
+	if c := classifyRegistered(err); c != Unknown {
+		return c
+	}
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		return mysqlErrors[me.Number]
+	}
+	var ke *gokb.Error
+	if errors.As(err, &ke) {
+		return classifySQLState(string(ke.Code))
+	}
+	var de *dm.DmError
+	if errors.As(err, &de) {
+		return classifyDM(de)
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return ConnectionLost
+	}
+	// 超时和上下文取消不代表连接已断开，不能当作 ConnectionLost 重试
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return Timeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return Unknown
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return Timeout
+		}
+		return ConnectionLost
+	}
+	return Unknown
+}
+
+func classifyRegistered(err error) Category {
+	classifiers.RLock()
+	defer classifiers.RUnlock()
+	for _, classify := range classifiers.s {
+		if c := classify(err); c != Unknown {
+			return c
+		}
+	}
+	return Unknown
+}
+
+// classifyError 将可识别的错误包装为 *Error，作为连接的 ErrorMapper
+func classifyError(err error) error {
+	c := Classify(err)
+	if c == Unknown {
+		return err
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	return &Error{Category: c, Err: err}
+}
+
+// mysqlErrors 为 MySQL 系的错误码，OceanBase 的错误码已转换为对应的 MySQL 错误码
+var mysqlErrors = map[uint16]Category{
+	1022: UniqueViolation,      // ER_DUP_KEY
+	1062: UniqueViolation,      // ER_DUP_ENTRY
+	1586: UniqueViolation,      // ER_DUP_ENTRY_WITH_KEY_NAME
+	1216: ForeignKeyViolation,  // ER_NO_REFERENCED_ROW
+	1217: ForeignKeyViolation,  // ER_ROW_IS_REFERENCED
+	1451: ForeignKeyViolation,  // ER_ROW_IS_REFERENCED_2
+	1452: ForeignKeyViolation,  // ER_NO_REFERENCED_ROW_2
+	1213: Deadlock,             // ER_LOCK_DEADLOCK
+	1205: LockTimeout,          // ER_LOCK_WAIT_TIMEOUT
+	3572: LockTimeout,          // ER_LOCK_NOWAIT
+	9007: SerializationFailure, // TiDB 乐观事务写冲突
+	8022: SerializationFailure, // TiDB 事务提交冲突
+	1053: ConnectionLost,       // ER_SERVER_SHUTDOWN
+	1927: ConnectionLost,       // ER_CONNECTION_KILLED
+	1290: ReadOnly,             // ER_OPTION_PREVENTS_STATEMENT，如 --read-only
+	1792: ReadOnly,             // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+	1064: SyntaxError,          // ER_PARSE_ERROR
+	1149: SyntaxError,          // ER_SYNTAX_ERROR
+	1044: PermissionDenied,     // ER_DBACCESS_DENIED_ERROR
+	1045: PermissionDenied,     // ER_ACCESS_DENIED_ERROR
+	1142: PermissionDenied,     // ER_TABLEACCESS_DENIED_ERROR
+	1143: PermissionDenied,     // ER_COLUMNACCESS_DENIED_ERROR
+	1227: PermissionDenied,     // ER_SPECIFIC_ACCESS_DENIED_ERROR
+}
+
+// classifySQLState 按 SQLSTATE 分类 Kingbase/PostgreSQL/openGauss 的错误
+func classifySQLState(code string) Category {
+	switch code {
+	case "23505":
+		return UniqueViolation
+	case "23503":
+		return ForeignKeyViolation
+	case "40P01":
+		return Deadlock
+	case "55P03":
+		return LockTimeout
+	case "40001":
+		return SerializationFailure
+	case "57P01", "57P02", "57P03":
+		return ConnectionLost
+	case "25006":
+		return ReadOnly
+	case "42601":
+		return SyntaxError
+	case "42501", "28000", "28P01":
+		return PermissionDenied
+	}
+	if strings.HasPrefix(code, "08") {
+		return ConnectionLost
+	}
+	return Unknown
+}
+
+// dmErrors 为 DM8 的错误码
+var dmErrors = map[int32]Category{
+	-6602:  UniqueViolation,     // 违反唯一性约束
+	-6625:  ForeignKeyViolation, // 违反引用约束
+	-6403:  Deadlock,            // 死锁
+	-2007:  SyntaxError,         // 语法分析出错
+	-6001:  ConnectionLost,      // 网络通信异常
+	-70019: ConnectionLost,      // 连接已关闭
+}
+
+// dmMessages 为错误码未收录时按错误信息分类的关键字
+var dmMessages = []struct {
+	keyword  string
+	category Category
+}{
+	{"唯一性约束", UniqueViolation},
+	{"引用约束", ForeignKeyViolation},
+	{"死锁", Deadlock},
+	{"锁超时", LockTimeout},
+	{"只读", ReadOnly},
+	{"语法分析出错", SyntaxError},
+	{"权限", PermissionDenied},
+	{"网络通信异常", ConnectionLost},
+}
+
+func classifyDM(e *dm.DmError) Category {
+	if c, ok := dmErrors[e.ErrCode]; ok {
+		return c
+	}
+	for _, m := range dmMessages {
+		if strings.Contains(e.ErrText, m.keyword) {
+			return m.category
+		}
+	}
+	return Unknown
+}
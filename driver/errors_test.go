@@ -0,0 +1,84 @@
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"gitee.com/chunanyong/dm"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/oceanbase"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{"nil", nil, Unknown},
+		{"mysql duplicate", &mysql.MySQLError{Number: 1062}, UniqueViolation},
+		{"mysql wrapped", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1452}), ForeignKeyViolation},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, Deadlock},
+		{"mysql read only", &mysql.MySQLError{Number: 1290}, ReadOnly},
+		{"tidb write conflict", &mysql.MySQLError{Number: 9007}, SerializationFailure},
+		{"oceanbase lock conflict", oceanbase.MapError(&mysql.MySQLError{Number: 6005}), LockTimeout},
+		{"mysql invalid conn", mysql.ErrInvalidConn, ConnectionLost},
+		{"kingbase unique", &gokb.Error{Code: "23505"}, UniqueViolation},
+		{"kingbase lock", &gokb.Error{Code: "55P03"}, LockTimeout},
+		{"kingbase serialization", &gokb.Error{Code: "40001"}, SerializationFailure},
+		{"kingbase connection", &gokb.Error{Code: "08006"}, ConnectionLost},
+		{"kingbase privilege", &gokb.Error{Code: "42501"}, PermissionDenied},
+		{"dm unique", &dm.DmError{ErrCode: -6602}, UniqueViolation},
+		{"dm message", &dm.DmError{ErrCode: -1, ErrText: "锁超时"}, LockTimeout},
+		{"bad conn", driver.ErrBadConn, ConnectionLost},
+		{"net closed", &net.OpError{Op: "read", Err: net.ErrClosed}, ConnectionLost},
+		{"net timeout", &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, Timeout},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), Timeout},
+		{"canceled", context.Canceled, Unknown},
+		{"other", errors.New("other"), Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+	assert.Equal(t, "UniqueViolation", UniqueViolation.String())
+	assert.Equal(t, "Timeout", Timeout.String())
+}
+
+func TestWrapErrors(t *testing.T) {
+	dup := &mysql.MySQLError{Number: 1062}
+	_, err := wrapConn(nil, dup)
+	assert.Same(t, dup, err)
+
+	SetWrapErrors(true)
+	defer SetWrapErrors(false)
+	_, err = wrapConn(nil, dup)
+	assert.ErrorIs(t, err, ErrUniqueViolation)
+	var me *mysql.MySQLError
+	assert.ErrorAs(t, err, &me)
+}
+
+type customError struct{}
+
+func (customError) Error() string { return "custom" }
+
+func TestRegisterClassifier(t *testing.T) {
+	assert.Equal(t, Unknown, Classify(customError{}))
+	RegisterClassifier(func(err error) Category {
+		if errors.As(err, new(customError)) {
+			return Deadlock
+		}
+		return Unknown
+	})
+	assert.Equal(t, Deadlock, Classify(fmt.Errorf("exec: %w", customError{})))
+	assert.Equal(t, UniqueViolation, Classify(&mysql.MySQLError{Number: 1062}))
+}
@@ -3,6 +3,10 @@ package kingbase
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
+	"io"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb"
 )
 
 type KBConn struct {
@@ -32,3 +36,47 @@ func (KC KBConn) Begin() (driver.Tx, error) {
 func (KC KBConn) Close() error {
 	return KC.conn.Close()
 }
+
+// DatabaseMode 返回 Kingbase 的数据库兼容模式，如 pg、oracle、mysql、sqlserver
+func (KC KBConn) DatabaseMode() string {
+	if m, ok := KC.conn.(interface{ DatabaseMode() string }); ok {
+		return m.DatabaseMode()
+	}
+	return ""
+}
+
+// LastInsertIDEnabled 返回连接是否开启了 get_last_insert_id，开启后才支持 LastInsertId
+func (KC KBConn) LastInsertIDEnabled() bool {
+	if m, ok := KC.conn.(interface{ LastInsertIDEnabled() bool }); ok {
+		return m.LastInsertIDEnabled()
+	}
+	return false
+}
+
+// ServerVersion 返回服务端在 ParameterStatus 中发送的 server_version
+func (KC KBConn) ServerVersion() string {
+	if m, ok := KC.conn.(interface{ ServerVersion() string }); ok {
+		return m.ServerVersion()
+	}
+	return ""
+}
+
+// CopyTo 执行 COPY ... TO STDOUT 语句，将导出的数据按原样写入 w，返回导出的行数和写入的字节数
+func (KC KBConn) CopyTo(ctx context.Context, w io.Writer, query string) (gokb.CopyStats, error) {
+	if c, ok := KC.conn.(interface {
+		CopyTo(ctx context.Context, w io.Writer, query string) (gokb.CopyStats, error)
+	}); ok {
+		return c.CopyTo(ctx, w, query)
+	}
+	return gokb.CopyStats{}, errors.New("kingbase: the connection does not support COPY TO")
+}
+
+// CopyFrom 执行 COPY ... FROM STDIN 语句，将从 r 中读取的数据按原样发送给服务端，返回导入的行数
+func (KC KBConn) CopyFrom(ctx context.Context, r io.Reader, query string) (int64, error) {
+	if c, ok := KC.conn.(interface {
+		CopyFrom(ctx context.Context, r io.Reader, query string) (int64, error)
+	}); ok {
+		return c.CopyFrom(ctx, r, query)
+	}
+	return 0, errors.New("kingbase: the connection does not support COPY FROM")
+}
@@ -26,5 +26,9 @@ func FormatDSN(cfg mysql.Config) string {
 	}
 	dsn += fmt.Sprintf("connect_timeout=%d ", cfg.Timeout/(1000*1000*1000))
 	dsn += "sslmode=disable dbname=proton"
+	// get_last_insert_id=yes 时 gokb 在 insert 语句末尾拼接 returning * 以支持 LastInsertId
+	if v, ok := cfg.Params["get_last_insert_id"]; ok {
+		dsn += fmt.Sprintf(" get_last_insert_id=%s", v)
+	}
 	return dsn
 }
@@ -33,6 +33,11 @@ func TestFormatDSN(t *testing.T) {
 			args: "username:password@tcp(localhost:3306)/?timeout=10s&readTimeout=10s&writeTimeout=10s&autocommit=true)",
 			want: "user=username password=password host=localhost port=3306 connect_timeout=10 sslmode=disable dbname=proton",
 		},
+		{
+			name: "case5",
+			args: "username:password@tcp(localhost:3306)/test?get_last_insert_id=yes",
+			want: "user=username password=password host=localhost port=3306 search_path=test connect_timeout=0 sslmode=disable dbname=proton get_last_insert_id=yes",
+		},
 	}
 	for _, tt := range tests {
 		cfg, err := common.ParseMySQLDSN(tt.args)
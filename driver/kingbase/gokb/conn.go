@@ -46,6 +46,7 @@ import (
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/mysqlOid"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/oracleOid"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/pgOid"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/sqlserverOid"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/scram"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/sm3"
@@ -563,8 +564,18 @@ func (c *Connector) open(ctx context.Context) (cn *conn, err error) {
 	}
 	panicking = false
 
-	// 获取数据库模式
-	getDatabaseMode(cn)
+	// 获取数据库模式，连接参数中指定了database_mode时不再向服务端查询
+	if mode, ok := o["database_mode"]; ok {
+		cn.databaseMode = mode
+		setDatabaseModeOid(cn)
+	} else {
+		getDatabaseMode(cn)
+	}
+	// PostgreSQL、openGauss使用标准的pg_catalog类型oid，Kingbase的pg模式仍使用原有的oid
+	if "true" == o["pg_catalog_oid"] {
+		cn.allOid = pgOid.PgOid
+		cn.TypeName = pgOid.TypeName
+	}
 
 	return
 }
@@ -626,6 +637,21 @@ func getDatabaseMode(cn *conn) {
 	setDatabaseModeOid(cn)
 }
 
+// DatabaseMode返回连接的数据库兼容模式(pg、oracle、mysql、sqlserver)
+func (cn *conn) DatabaseMode() string {
+	return cn.databaseMode
+}
+
+// ServerVersion返回服务端在ParameterStatus中发送的server_version
+func (cn *conn) ServerVersion() string {
+	return cn.parameterStatus.serverVersionString
+}
+
+// LastInsertIDEnabled返回连接是否开启了get_last_insert_id
+func (cn *conn) LastInsertIDEnabled() bool {
+	return cn.getLastInserttId.enable
+}
+
 func setDatabaseModeOid(cn *conn) {
 	if cn.databaseMode == "sqlserver" {
 		cn.allOid = sqlserverOid.SqlserverOid
@@ -1756,6 +1782,10 @@ func isDriverSetting(key string) (state bool) {
 	case "disable_prepared_binary_result":
 		fallthrough
 	case "binary_parameters":
+		fallthrough
+	case "database_mode":
+		fallthrough
+	case "pg_catalog_oid":
 		state = true
 	default:
 		state = false
@@ -2662,7 +2692,8 @@ func (cn *conn) processParameterStatus(rb *readBuf) {
 	switch param {
 	case "server_version":
 		var major1, major2, minor int
-		_, err = fmt.Sscanf(rb.string(), "%d.%d.%d", &major1, &major2, &minor)
+		cn.parameterStatus.serverVersionString = rb.string()
+		_, err = fmt.Sscanf(cn.parameterStatus.serverVersionString, "%d.%d.%d", &major1, &major2, &minor)
 		if nil == err {
 			cn.parameterStatus.serverVersion = major1*10000 + major2*100 + minor
 		}
@@ -21,9 +21,13 @@
 package gokb
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"math"
+	"time"
 )
 
 // CopyIn创建一个可用Tx.Prepare()处理的'COPY FROM'预备语句
@@ -58,11 +62,20 @@ func (cn *conn) prepareCopyIn(query string) (_ driver.Stmt, err error) {
 		return nil, errCopyNotSupportedOutsideTxn
 	}
 
+	// 二进制格式需要在COPY开始前查询目标列的类型
+	var encoders []copyEncoder
+	if isBinaryCopyIn(query) {
+		if encoders, err = cn.copyEncoders(query); nil != err {
+			return nil, err
+		}
+	}
+
 	ci := &copyin{
-		cn:      cn,
-		buffer:  make([]byte, 0, ciBufferSize),
-		rowData: make(chan []byte),
-		done:    make(chan bool, 1),
+		cn:       cn,
+		buffer:   make([]byte, 0, ciBufferSize),
+		rowData:  make(chan []byte),
+		done:     make(chan bool, 1),
+		encoders: encoders,
 	}
 	// 添加CopyData的标识符和四字节的消息长度
 	ci.buffer = append(ci.buffer, 'd', 0, 0, 0, 0)
@@ -76,10 +89,13 @@ awaitCopyInResponse:
 		t, r := cn.recv1()
 		switch t {
 		case 'G':
-			if 0 != r.byte() {
+			if (0 != r.byte()) != (nil != encoders) {
 				err = errBinaryCopyNotSupported
 				break awaitCopyInResponse
 			}
+			if nil != encoders {
+				ci.buffer = append(ci.buffer, binaryCopyHeader...)
+			}
 			go ci.resploop()
 			return ci, nil
 		case 'H':
@@ -228,15 +244,21 @@ func (ci *copyin) Exec(v []driver.Value) (driver.Result, error) {
 		return driver.RowsAffected(0), ci.Close()
 	}
 
-	numValues := len(v)
-	for i, value := range v {
-		ci.buffer = appendEncodedText(&ci.cn.parameterStatus, ci.buffer, value)
-		if numValues-1 > i {
-			ci.buffer = append(ci.buffer, '\t')
+	if nil != ci.encoders {
+		if err = ci.appendBinaryRow(v); nil != err {
+			return nil, err
+		}
+	} else {
+		numValues := len(v)
+		for i, value := range v {
+			ci.buffer = appendEncodedText(&ci.cn.parameterStatus, ci.buffer, value)
+			if numValues-1 > i {
+				ci.buffer = append(ci.buffer, '\t')
+			}
 		}
-	}
 
-	ci.buffer = append(ci.buffer, '\n')
+		ci.buffer = append(ci.buffer, '\n')
+	}
 
 	if ciBufferFlushSize < len(ci.buffer) {
 		ci.flush(ci.buffer)
@@ -247,6 +269,36 @@ func (ci *copyin) Exec(v []driver.Value) (driver.Result, error) {
 	return driver.RowsAffected(0), nil
 }
 
+// appendBinaryRow将一行数据按二进制格式追加到缓冲区，编码出错时缓冲区保持不变
+func (ci *copyin) appendBinaryRow(v []driver.Value) (err error) {
+	if len(ci.encoders) != len(v) {
+		return fmt.Errorf("kb: got %d values for %d columns in binary COPY", len(v), len(ci.encoders))
+	}
+	start := len(ci.buffer)
+	ci.buffer = binary.BigEndian.AppendUint16(ci.buffer, uint16(len(v)))
+	for i, value := range v {
+		if vr, ok := value.(driver.Valuer); ok {
+			if value, err = callValuerValue(vr); nil != err {
+				ci.buffer = ci.buffer[:start]
+				return err
+			}
+		}
+		if nil == value {
+			ci.buffer = binary.BigEndian.AppendUint32(ci.buffer, math.MaxUint32)
+			continue
+		}
+		// 预留字段长度，编码后回填
+		pos := len(ci.buffer)
+		ci.buffer = append(ci.buffer, 0, 0, 0, 0)
+		if ci.buffer, err = ci.encoders[i](ci.buffer, value); nil != err {
+			ci.buffer = ci.buffer[:start]
+			return err
+		}
+		binary.BigEndian.PutUint32(ci.buffer[pos:], uint32(len(ci.buffer)-pos-4))
+	}
+	return nil
+}
+
 func (ci *copyin) Close() error {
 	var err error
 	if ci.closed {
@@ -259,6 +311,10 @@ func (ci *copyin) Close() error {
 	}
 	defer ci.cn.errRecover(&err)
 
+	if nil != ci.encoders {
+		// 二进制格式的结束标志为字段数-1
+		ci.buffer = append(ci.buffer, 0xff, 0xff)
+	}
 	if 0 < len(ci.buffer) {
 		ci.flush(ci.buffer)
 	}
@@ -276,3 +332,214 @@ func (ci *copyin) Close() error {
 	}
 	return nil
 }
+
+// CopyFormat为COPY的数据格式
+type CopyFormat string
+
+const (
+	CopyText   CopyFormat = "text"
+	CopyCSV    CopyFormat = "csv"
+	CopyBinary CopyFormat = "binary"
+)
+
+// CopyOut创建一个可用于CopyTo的'COPY TO STDOUT'语句，format为空时使用text格式，columns为空时导出所有列
+// 目标表需要在当前的search_path下
+func CopyOut(table string, format CopyFormat, columns ...string) (stmt string) {
+	return copyOut(QuoteIdentifier(table), format, columns)
+}
+
+// CopyOutSchema创建一个可用于CopyTo的'COPY TO STDOUT'语句
+func CopyOutSchema(schema, table string, format CopyFormat, columns ...string) (stmt string) {
+	return copyOut(QuoteIdentifier(schema)+"."+QuoteIdentifier(table), format, columns)
+}
+
+func copyOut(target string, format CopyFormat, columns []string) (stmt string) {
+	stmt = "COPY " + target
+	if 0 != len(columns) {
+		stmt += " ("
+		for i, col := range columns {
+			if 0 != i {
+				stmt += ", "
+			}
+			stmt += QuoteIdentifier(col)
+		}
+		stmt += ")"
+	}
+	stmt += " TO STDOUT"
+	if "" != format && CopyText != format {
+		stmt += " WITH (FORMAT " + string(format) + ")"
+	}
+	return stmt
+}
+
+// CopyStats为CopyTo的统计信息
+type CopyStats struct {
+	// Rows为服务端在CommandComplete中返回的行数
+	Rows int64
+	// Bytes为写入io.Writer的字节数
+	Bytes int64
+}
+
+// CopyTo执行'COPY ... TO STDOUT'语句，将服务端返回的CopyData报文的内容按原样写入w，
+// 数据格式由语句指定，可以是text、csv或binary。不需要在事务中执行
+// ctx被取消或写入w出错时向服务端发送CancelRequest，读取并丢弃剩余的报文后返回错误，连接可以继续使用
+func (cn *conn) CopyTo(ctx context.Context, w io.Writer, query string) (stats CopyStats, err error) {
+	if cn.bad {
+		return stats, driver.ErrBadConn
+	}
+	if cn.inCopy {
+		return stats, errCopyInProgress
+	}
+	defer cn.errRecover(&err)
+
+	if finish := cn.watchCancel(ctx); nil != finish {
+		defer finish()
+	}
+
+	b := cn.writeBuf('Q')
+	b.string(query)
+	cn.send(b)
+
+	var r readBuf
+	var writeErr error
+	for {
+		t := cn.recv1Buf(&r)
+		switch t {
+		case 'H':
+			cn.inCopy = true
+		case 'd':
+			if nil != writeErr {
+				continue
+			}
+			n, e := w.Write(r)
+			stats.Bytes += int64(n)
+			if nil != e {
+				// 停止导出，剩余的CopyData报文将被丢弃
+				writeErr = e
+				ctxCancel, cancel := context.WithTimeout(context.Background(), time.Second*10)
+				_ = cn.cancel(ctxCancel)
+				cancel()
+			}
+		case 'c':
+			cn.inCopy = false
+		case 'C':
+			res, _ := cn.parseComplete(r.string(), 0)
+			stats.Rows, _ = res.RowsAffected()
+		case 'G':
+			// COPY FROM STDIN，发送CopyFail终止
+			err = errCopyFromNotSupported
+			wb := cn.writeBuf('f')
+			wb.string(err.Error())
+			cn.send(wb)
+		case 'T', 'D', 'I':
+			if nil == err {
+				err = errNotCopyTo
+			}
+		case 'E':
+			cn.inCopy = false
+			if nil == err {
+				err = parseError(&r)
+			}
+		case 'Z':
+			cn.processReadyForQuery(&r)
+			if nil != writeErr {
+				return stats, writeErr
+			}
+			return stats, err
+		default:
+			cn.bad = true
+			errorf("unknown response for COPY TO: %q", t)
+		}
+	}
+}
+
+// CopyFrom执行'COPY ... FROM STDIN'语句，将从r中读取的数据按原样作为CopyData报文发送给服务端，
+// r中数据的格式需与语句指定的格式一致。不需要在事务中执行，返回服务端在CommandComplete中返回的行数
+// ctx被取消或读取r出错时发送CopyFail终止COPY，服务端回滚本次导入的所有数据
+func (cn *conn) CopyFrom(ctx context.Context, r io.Reader, query string) (rows int64, err error) {
+	if cn.bad {
+		return 0, driver.ErrBadConn
+	}
+	if cn.inCopy {
+		return 0, errCopyInProgress
+	}
+	defer cn.errRecover(&err)
+
+	if finish := cn.watchCancel(ctx); nil != finish {
+		defer finish()
+	}
+
+	b := cn.writeBuf('Q')
+	b.string(query)
+	cn.send(b)
+
+	var rb readBuf
+	var readErr error
+	for {
+		t := cn.recv1Buf(&rb)
+		switch t {
+		case 'G':
+			cn.inCopy = true
+			readErr = cn.sendCopyData(ctx, r)
+			if nil != readErr {
+				wb := cn.writeBuf('f')
+				wb.string(readErr.Error())
+				cn.send(wb)
+			} else if err = cn.sendSimpleMessage('c'); nil != err {
+				panic(err)
+			}
+			cn.inCopy = false
+		case 'H':
+			// COPY TO STDOUT，丢弃服务端返回的数据
+			err = errCopyToNotSupported
+		case 'd', 'c', 'T', 'D', 'I':
+			if nil == err {
+				err = errNotCopyFrom
+			}
+		case 'C':
+			res, _ := cn.parseComplete(rb.string(), 0)
+			rows, _ = res.RowsAffected()
+		case 'E':
+			if nil == err {
+				err = parseError(&rb)
+			}
+		case 'Z':
+			cn.processReadyForQuery(&rb)
+			if nil != readErr {
+				return 0, readErr
+			}
+			if nil != err {
+				return 0, err
+			}
+			return rows, nil
+		default:
+			cn.bad = true
+			errorf("unknown response for COPY FROM: %q", t)
+		}
+	}
+}
+
+// sendCopyData将r中的数据分段作为CopyData报文发送，返回读取r时的错误或ctx的错误
+func (cn *conn) sendCopyData(ctx context.Context, r io.Reader) error {
+	buf := make([]byte, ciBufferSize)
+	// 添加CopyData的标识符并为报文长度预留空间
+	buf[0] = 'd'
+	for {
+		if err := ctx.Err(); nil != err {
+			return err
+		}
+		n, err := r.Read(buf[5:])
+		if 0 < n {
+			binary.BigEndian.PutUint32(buf[1:], uint32(n+4))
+			if _, e := cn.c.Write(buf[:5+n]); nil != e {
+				panic(e)
+			}
+		}
+		if io.EOF == err {
+			return nil
+		}
+		if nil != err {
+			return err
+		}
+	}
+}
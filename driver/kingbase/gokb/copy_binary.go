@@ -0,0 +1,508 @@
+/******************************************************************************
+* 版权信息：中电科金仓（北京）科技股份有限公司
+
+* 作者：KingbaseES
+
+* 文件名：copy_binary.go
+
+* 功能描述：二进制格式COPY FROM的编码
+
+* 其它说明：
+
+* 修改记录：
+  1.修改时间：
+
+  2.修改人：
+
+  3.修改内容：
+
+******************************************************************************/
+
+package gokb
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid"
+)
+
+// binaryCopyHeader为二进制COPY的文件头：11字节的签名、4字节的标志位和4字节的头扩展区长度
+var binaryCopyHeader = []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+
+// binaryCopyPattern匹配COPY选项中的二进制格式
+var binaryCopyPattern = regexp.MustCompile(`(?i)^\s*(WITH\s*)?(BINARY\b|\(.*\bFORMAT\s+'?BINARY\b)`)
+
+// 二进制格式中日期和时间的起点2000-01-01
+var (
+	copyEpoch     = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	copyEpochDate = civil.Date{Year: 2000, Month: time.January, Day: 1}
+)
+
+// copyEncoder将一个非空值按二进制COPY格式追加到buf中，不含长度
+type copyEncoder func(buf []byte, v driver.Value) ([]byte, error)
+
+// CopyInBinary创建一个使用二进制格式的'COPY FROM'预备语句，目标表需要在当前的search_path下
+// 预备时按目标列的类型选择编码，Exec的参数按二进制格式发送，时间戳、numeric、bytea等类型比文本格式的开销更小
+func CopyInBinary(table string, columns ...string) (stmt string) {
+	return CopyIn(table, columns...) + " WITH (FORMAT binary)"
+}
+
+// CopyInSchemaBinary创建一个使用二进制格式的'COPY FROM'预备语句
+func CopyInSchemaBinary(schema, table string, columns ...string) (stmt string) {
+	return CopyInSchema(schema, table, columns...) + " WITH (FORMAT binary)"
+}
+
+// parseCopyIn从'COPY target [(columns)] FROM STDIN options'中取出表名、列名和选项，没有列名时columns为空
+func parseCopyIn(query string) (target, columns, options string, ok bool) {
+	s := strings.TrimSpace(query)
+	if 4 > len(s) || !strings.EqualFold(s[:4], "COPY") {
+		return "", "", "", false
+	}
+	s = strings.TrimSpace(s[4:])
+
+	// 表名在第一个引号外的空白或左括号处结束
+	quoted, end := false, len(s)
+	for i := 0; len(s) > i; i++ {
+		c := s[i]
+		if '"' == c {
+			quoted = !quoted
+		} else if !quoted && (' ' == c || '\t' == c || '\n' == c || '(' == c) {
+			end = i
+			break
+		}
+	}
+	target, s = s[:end], strings.TrimSpace(s[end:])
+	if 0 != len(s) && '(' == s[0] {
+		quoted, end = false, -1
+		for i := 1; len(s) > i; i++ {
+			c := s[i]
+			if '"' == c {
+				quoted = !quoted
+			} else if !quoted && ')' == c {
+				end = i
+				break
+			}
+		}
+		if 0 > end {
+			return "", "", "", false
+		}
+		columns, s = s[1:end], strings.TrimSpace(s[end+1:])
+	}
+	fields := strings.Fields(s)
+	if 2 > len(fields) || !strings.EqualFold(fields[0], "FROM") || !strings.EqualFold(fields[1], "STDIN") {
+		return "", "", "", false
+	}
+	options = strings.TrimSpace(s[strings.Index(strings.ToUpper(s), "STDIN")+5:])
+	return target, columns, options, "" != target
+}
+
+// isBinaryCopyIn判断COPY FROM STDIN语句是否使用二进制格式
+func isBinaryCopyIn(query string) (result bool) {
+	_, _, options, ok := parseCopyIn(query)
+	return ok && binaryCopyPattern.MatchString(options)
+}
+
+// copyEncoders查询目标列的类型并返回各列的编码函数，必须在发送COPY语句之前调用
+func (cn *conn) copyEncoders(query string) (encoders []copyEncoder, err error) {
+	target, columns, _, ok := parseCopyIn(query)
+	if !ok {
+		return nil, fmt.Errorf("kb: cannot parse the target of binary COPY: %s", query)
+	}
+	if "" == columns {
+		columns = "*"
+	}
+	rs, err := cn.simpleQuery("SELECT " + columns + " FROM " + target + " WHERE 1 = 0")
+	if nil != err {
+		return nil, err
+	}
+	if nil == rs {
+		return nil, fmt.Errorf("kb: cannot describe the target of binary COPY: %s", query)
+	}
+	defer rs.Close()
+
+	encoders = make([]copyEncoder, len(rs.colTyps))
+	for i, typ := range rs.colTyps {
+		if encoders[i] = cn.copyEncoder(typ.OID); nil == encoders[i] {
+			name := cn.TypeName[typ.OID]
+			if "" == name {
+				name = strconv.FormatUint(uint64(typ.OID), 10)
+			}
+			return nil, fmt.Errorf("kb: binary COPY does not support column %q of type %s", rs.colNames[i], name)
+		}
+	}
+	return encoders, nil
+}
+
+// copyEncoder返回类型对应的二进制编码函数，不支持的类型返回nil
+func (cn *conn) copyEncoder(typ oid.Oid) copyEncoder {
+	switch typ {
+	case cn.allOid.T_bool:
+		return encodeCopyBool
+	case cn.allOid.T_int2, cn.allOid.T_smallint:
+		return encodeCopyInt(2)
+	case cn.allOid.T_int4, cn.allOid.T_int:
+		return encodeCopyInt(4)
+	case cn.allOid.T_int8, cn.allOid.T_bigint:
+		return encodeCopyInt(8)
+	case cn.allOid.T_float4:
+		return encodeCopyFloat4
+	case cn.allOid.T_float8:
+		return encodeCopyFloat8
+	case cn.allOid.T_numeric:
+		return encodeCopyNumeric
+	case cn.allOid.T_text, cn.allOid.T_varchar, cn.allOid.T_bpchar, cn.allOid.T_name, cn.allOid.T_json:
+		return cn.encodeCopyText
+	case cn.allOid.T_jsonb:
+		return func(buf []byte, v driver.Value) ([]byte, error) {
+			// jsonb的二进制格式为版本号1和JSON文本
+			return cn.encodeCopyText(append(buf, 1), v)
+		}
+	case cn.allOid.T_bytea:
+		return encodeCopyBytea
+	case cn.allOid.T_date:
+		// Oracle、MySQL模式的DATE包含时间，取值按timestamp解析，二进制格式与timestamp相同
+		if "pg" == cn.databaseMode || "sqlserver" == cn.databaseMode {
+			return encodeCopyDate
+		}
+		return encodeCopyOraDate
+	case oid.T_ora_date:
+		return encodeCopyOraDate
+	case cn.allOid.T_time:
+		return encodeCopyTime
+	case cn.allOid.T_timestamp:
+		return encodeCopyTimestamp
+	case cn.allOid.T_timestamptz:
+		return encodeCopyTimestamptz
+	case cn.allOid.T_uuid:
+		return encodeCopyUUID
+	}
+	return nil
+}
+
+func copyTypeError(v driver.Value, typ string) error {
+	return fmt.Errorf("kb: cannot encode %T as %s in binary COPY", v, typ)
+}
+
+func encodeCopyBool(buf []byte, v driver.Value) ([]byte, error) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case string:
+		b, err := strconv.ParseBool(x)
+		if nil != err {
+			return buf, err
+		}
+		return encodeCopyBool(buf, b)
+	}
+	return buf, copyTypeError(v, "bool")
+}
+
+// encodeCopyInt返回size字节整数的编码函数，超出范围时返回错误
+func encodeCopyInt(size int) copyEncoder {
+	bits := size * 8
+	return func(buf []byte, v driver.Value) ([]byte, error) {
+		var n int64
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if math.MaxInt64 < rv.Uint() {
+				return buf, fmt.Errorf("kb: value %d out of range for int%d", rv.Uint(), size)
+			}
+			n = int64(rv.Uint())
+		case reflect.String:
+			var err error
+			if n, err = strconv.ParseInt(strings.TrimSpace(rv.String()), 10, bits); nil != err {
+				return buf, err
+			}
+		default:
+			return buf, copyTypeError(v, "int"+strconv.Itoa(size))
+		}
+		if n < -1<<(bits-1) || n > 1<<(bits-1)-1 {
+			return buf, fmt.Errorf("kb: value %d out of range for int%d", n, size)
+		}
+		switch size {
+		case 2:
+			return binary.BigEndian.AppendUint16(buf, uint16(n)), nil
+		case 4:
+			return binary.BigEndian.AppendUint32(buf, uint32(n)), nil
+		}
+		return binary.BigEndian.AppendUint64(buf, uint64(n)), nil
+	}
+}
+
+// copyFloat将数值类型、decimal.Decimal和字符串转换为float64
+func copyFloat(v driver.Value, typ string) (f float64, err error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+	}
+	if d, ok := v.(decimal.Decimal); ok {
+		return d.InexactFloat64(), nil
+	}
+	return 0, copyTypeError(v, typ)
+}
+
+func encodeCopyFloat4(buf []byte, v driver.Value) ([]byte, error) {
+	f, err := copyFloat(v, "float4")
+	if nil != err {
+		return buf, err
+	}
+	return binary.BigEndian.AppendUint32(buf, math.Float32bits(float32(f))), nil
+}
+
+func encodeCopyFloat8(buf []byte, v driver.Value) ([]byte, error) {
+	f, err := copyFloat(v, "float8")
+	if nil != err {
+		return buf, err
+	}
+	return binary.BigEndian.AppendUint64(buf, math.Float64bits(f)), nil
+}
+
+// encodeCopyNumeric将数值按numeric的二进制格式编码：位数、权重、符号、小数位数和以10000为基的各位
+func encodeCopyNumeric(buf []byte, v driver.Value) ([]byte, error) {
+	var s string
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			s = "NaN"
+		} else if math.IsInf(rv.Float(), 0) {
+			return buf, fmt.Errorf("kb: cannot encode %v as numeric", rv.Float())
+		} else {
+			s = strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+		}
+	case reflect.String:
+		s = strings.TrimSpace(rv.String())
+	default:
+		d, ok := v.(decimal.Decimal)
+		if !ok {
+			return buf, copyTypeError(v, "numeric")
+		}
+		s = d.String()
+		if 0 > d.Exponent() {
+			s = d.StringFixed(-d.Exponent())
+		}
+	}
+	return appendNumeric(buf, s)
+}
+
+// appendNumeric将十进制数的文本按numeric的二进制格式追加到buf中
+func appendNumeric(buf []byte, s string) ([]byte, error) {
+	if strings.EqualFold(s, "NaN") {
+		return append(buf, 0, 0, 0, 0, 0xC0, 0, 0, 0), nil
+	}
+	if strings.ContainsAny(s, "eE") {
+		d, err := decimal.NewFromString(s)
+		if nil != err {
+			return buf, err
+		}
+		s = d.String()
+		if 0 > d.Exponent() {
+			s = d.StringFixed(-d.Exponent())
+		}
+	}
+	var sign uint16
+	if strings.HasPrefix(s, "-") {
+		sign, s = 0x4000, s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if "" == intPart && "" == fracPart {
+		return buf, fmt.Errorf("kb: invalid numeric %q", s)
+	}
+	for _, c := range intPart + fracPart {
+		if '0' > c || '9' < c {
+			return buf, fmt.Errorf("kb: invalid numeric %q", s)
+		}
+	}
+	dscale := len(fracPart)
+
+	// 整数部分在左侧、小数部分在右侧补0，按4位一组转换为以10000为基的各位
+	intPart = strings.TrimLeft(intPart, "0")
+	intPart = strings.Repeat("0", (4-len(intPart)%4)%4) + intPart
+	fracPart += strings.Repeat("0", (4-len(fracPart)%4)%4)
+	all := intPart + fracPart
+	digits := make([]uint16, 0, len(all)/4)
+	for i := 0; len(all) > i; i += 4 {
+		d, _ := strconv.Atoi(all[i : i+4])
+		digits = append(digits, uint16(d))
+	}
+	weight := len(intPart)/4 - 1
+	for 0 < len(digits) && 0 == digits[0] {
+		digits, weight = digits[1:], weight-1
+	}
+	for 0 < len(digits) && 0 == digits[len(digits)-1] {
+		digits = digits[:len(digits)-1]
+	}
+	if 0 == len(digits) {
+		sign, weight = 0, 0
+	}
+
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(digits)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(int16(weight)))
+	buf = binary.BigEndian.AppendUint16(buf, sign)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(dscale))
+	for _, d := range digits {
+		buf = binary.BigEndian.AppendUint16(buf, d)
+	}
+	return buf, nil
+}
+
+// encodeCopyText按文本发送字符串类型，二进制格式与文本格式相同
+func (cn *conn) encodeCopyText(buf []byte, v driver.Value) ([]byte, error) {
+	switch x := v.(type) {
+	case string:
+		return append(buf, x...), nil
+	case []byte:
+		return append(buf, x...), nil
+	}
+	return append(buf, encode(&cn.parameterStatus, v, cn.allOid.T_text, cn)...), nil
+}
+
+func encodeCopyBytea(buf []byte, v driver.Value) ([]byte, error) {
+	switch x := v.(type) {
+	case []byte:
+		return append(buf, x...), nil
+	case string:
+		return append(buf, x...), nil
+	}
+	return buf, copyTypeError(v, "bytea")
+}
+
+func encodeCopyDate(buf []byte, v driver.Value) ([]byte, error) {
+	var d civil.Date
+	switch x := v.(type) {
+	case time.Time:
+		d = civil.DateOf(x)
+	case civil.Date:
+		d = x
+	case string:
+		var err error
+		if d, err = civil.ParseDate(strings.TrimSpace(x)); nil != err {
+			return buf, err
+		}
+	default:
+		return buf, copyTypeError(v, "date")
+	}
+	return binary.BigEndian.AppendUint32(buf, uint32(int32(d.DaysSince(copyEpochDate)))), nil
+}
+
+// encodeCopyOraDate按timestamp编码Oracle、MySQL模式的DATE，日期和文本按当天零点处理
+func encodeCopyOraDate(buf []byte, v driver.Value) ([]byte, error) {
+	switch x := v.(type) {
+	case civil.Date:
+		v = x.In(time.UTC)
+	case string:
+		x = strings.TrimSpace(x)
+		if d, err := civil.ParseDate(x); nil == err {
+			v = d.In(time.UTC)
+		} else if t, err := time.Parse("2006-01-02 15:04:05.999999999", x); nil == err {
+			v = t
+		} else {
+			return buf, err
+		}
+	}
+	out, err := encodeCopyTimestamp(buf, v)
+	if nil != err {
+		return buf, copyTypeError(v, "date")
+	}
+	return out, nil
+}
+
+// copyMicros返回时、分、秒和纳秒对应的微秒数
+func copyMicros(hour, minute, second, nanosecond int) int64 {
+	return ((int64(hour)*60+int64(minute))*60+int64(second))*1000000 + int64(nanosecond)/1000
+}
+
+func encodeCopyTime(buf []byte, v driver.Value) ([]byte, error) {
+	var t civil.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = civil.TimeOf(x)
+	case civil.Time:
+		t = x
+	case string:
+		var err error
+		if t, err = civil.ParseTime(strings.TrimSpace(x)); nil != err {
+			return buf, err
+		}
+	default:
+		return buf, copyTypeError(v, "time")
+	}
+	return binary.BigEndian.AppendUint64(buf, uint64(copyMicros(t.Hour, t.Minute, t.Second, t.Nanosecond))), nil
+}
+
+// encodeCopyTimestamp按time.Time的本地日期和时间编码不带时区的timestamp
+func encodeCopyTimestamp(buf []byte, v driver.Value) ([]byte, error) {
+	var t time.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = x
+	case DateTime1:
+		t = time.Time(x)
+	default:
+		return buf, copyTypeError(v, "timestamp")
+	}
+	days := int64(civil.DateOf(t).DaysSince(copyEpochDate))
+	micros := days*86400000000 + copyMicros(t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+	return binary.BigEndian.AppendUint64(buf, uint64(micros)), nil
+}
+
+func encodeCopyTimestamptz(buf []byte, v driver.Value) ([]byte, error) {
+	var t time.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = x
+	case DateTime1:
+		t = time.Time(x)
+	default:
+		return buf, copyTypeError(v, "timestamptz")
+	}
+	return binary.BigEndian.AppendUint64(buf, uint64(t.UnixMicro()-copyEpoch.UnixMicro())), nil
+}
+
+func encodeCopyUUID(buf []byte, v driver.Value) ([]byte, error) {
+	switch x := v.(type) {
+	case []byte:
+		if 16 == len(x) {
+			return append(buf, x...), nil
+		}
+		return encodeCopyUUID(buf, string(x))
+	case string:
+		b, err := hex.DecodeString(strings.ReplaceAll(strings.Trim(x, "{}"), "-", ""))
+		if nil != err || 16 != len(b) {
+			return buf, fmt.Errorf("kb: invalid uuid %q", x)
+		}
+		return append(buf, b...), nil
+	}
+	return buf, copyTypeError(v, "uuid")
+}
@@ -0,0 +1,247 @@
+package gokb
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/mysqlOid"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/oracleOid"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/pgOid"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid/sqlserverOid"
+)
+
+func TestParseCopyIn(t *testing.T) {
+	tests := []struct {
+		query   string
+		target  string
+		columns string
+		binary  bool
+	}{
+		{CopyIn("t_user", "f_id", "f_name"), `"t_user"`, `"f_id", "f_name"`, false},
+		{CopyInBinary("t_user", "f_id"), `"t_user"`, `"f_id"`, true},
+		{CopyInSchemaBinary("public", "t (1)", "a)b"), `"public"."t (1)"`, `"a)b"`, true},
+		{"copy t from stdin with binary", "t", "", true},
+		{"COPY t(a) FROM STDIN WITH (FORMAT csv, HEADER true)", "t", "a", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			target, columns, _, ok := parseCopyIn(tt.query)
+			assert.True(t, ok)
+			assert.Equal(t, tt.target, target)
+			assert.Equal(t, tt.columns, columns)
+			assert.Equal(t, tt.binary, isBinaryCopyIn(tt.query))
+		})
+	}
+	_, _, _, ok := parseCopyIn(CopyOut("t", CopyBinary))
+	assert.False(t, ok)
+}
+
+func TestAppendNumeric(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []uint16
+	}{
+		{"0", []uint16{0, 0, 0, 0}},
+		{"12345.6", []uint16{3, 1, 0, 1, 1, 2345, 6000}},
+		{"-0.00001", []uint16{1, 0xfffe, 0x4000, 5, 1000}},
+		{"1.50", []uint16{2, 0, 0, 2, 1, 5000}},
+		{"10000", []uint16{1, 1, 0, 0, 1}},
+		{"NaN", []uint16{0, 0, 0xc000, 0}},
+		{"1e3", []uint16{1, 0, 0, 0, 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var want []byte
+			for _, w := range tt.want {
+				want = binary.BigEndian.AppendUint16(want, w)
+			}
+			got, err := appendNumeric(nil, tt.in)
+			assert.Nil(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+	_, err := appendNumeric(nil, "1.2.3")
+	assert.NotNil(t, err)
+}
+
+func TestCopyEncoders(t *testing.T) {
+	ts := time.Date(2000, 1, 2, 0, 0, 1, 0, time.UTC)
+	tests := []struct {
+		name string
+		enc  copyEncoder
+		v    driver.Value
+		want []byte
+	}{
+		{"int2", encodeCopyInt(2), int64(-2), []byte{0xff, 0xfe}},
+		{"int4 string", encodeCopyInt(4), "7", []byte{0, 0, 0, 7}},
+		{"bool", encodeCopyBool, true, []byte{1}},
+		{"date", encodeCopyDate, ts, []byte{0, 0, 0, 1}},
+		{"timestamp", encodeCopyTimestamp, ts, binary.BigEndian.AppendUint64(nil, 86401000000)},
+		{"timestamptz", encodeCopyTimestamptz, ts.In(time.FixedZone("CST", 8*3600)), binary.BigEndian.AppendUint64(nil, 86401000000)},
+		{"uuid", encodeCopyUUID, "00010203-0405-0607-0809-0a0b0c0d0e0f", []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.enc(nil, tt.v)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := encodeCopyInt(2)(nil, 70000)
+	assert.NotNil(t, err)
+	_, err = encodeCopyOraDate(nil, 1.5)
+	assert.NotNil(t, err)
+	_, err = encodeCopyBool(nil, 1.5)
+	assert.NotNil(t, err)
+}
+
+func TestCopyDateEncoder(t *testing.T) {
+	ts := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	date := []byte{0, 0, 0, 1}
+	tests := []struct {
+		mode   string
+		allOid oid.AllOid
+		v      driver.Value
+		want   []byte
+	}{
+		{"pg", pgOid.PgOid, ts, date},
+		{"pg", pgOid.PgOid, "2000-01-02", date},
+		{"sqlserver", sqlserverOid.SqlserverOid, ts, date},
+		{"oracle", oracleOid.OracleOid, ts, binary.BigEndian.AppendUint64(nil, 86400000000+11045000000)},
+		{"oracle", oracleOid.OracleOid, "2000-01-02", binary.BigEndian.AppendUint64(nil, 86400000000)},
+		{"oracle", oracleOid.OracleOid, "2000-01-02 03:04:05", binary.BigEndian.AppendUint64(nil, 86400000000+11045000000)},
+		{"", oracleOid.OracleOid, civil.Date{Year: 2000, Month: 1, Day: 2}, binary.BigEndian.AppendUint64(nil, 86400000000)},
+		{"mysql", mysqlOid.MysqlOid, ts, binary.BigEndian.AppendUint64(nil, 86400000000+11045000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cn := &conn{databaseMode: tt.mode, allOid: tt.allOid}
+			enc := cn.copyEncoder(tt.allOid.T_date)
+			if assert.NotNil(t, enc) {
+				got, err := enc(nil, tt.v)
+				assert.Nil(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	// Oracle的DATE在其它模式中同样按timestamp编码
+	cn := &conn{databaseMode: "mysql", allOid: mysqlOid.MysqlOid}
+	got, err := cn.copyEncoder(oid.T_ora_date)(nil, ts)
+	assert.Nil(t, err)
+	assert.Len(t, got, 8)
+}
+
+// copyServer模拟服务端：回复描述目标列的查询和COPY FROM STDIN，丢弃收到的CopyData
+func copyServer(b testing.TB, c net.Conn, typs []oid.Oid, binaryFormat bool) {
+	send := func(t byte, body []byte) {
+		m := append([]byte{t}, binary.BigEndian.AppendUint32(nil, uint32(len(body)+4))...)
+		if _, err := c.Write(append(m, body...)); err != nil {
+			b.Error(err)
+		}
+	}
+	head := make([]byte, 5)
+	var body []byte
+	for {
+		if _, err := io.ReadFull(c, head); err != nil {
+			return
+		}
+		body = append(body[:0], make([]byte, binary.BigEndian.Uint32(head[1:])-4)...)
+		if _, err := io.ReadFull(c, body); err != nil {
+			return
+		}
+		switch head[0] {
+		case 'Q':
+			if string(body[:6]) == "SELECT" {
+				desc := binary.BigEndian.AppendUint16(nil, uint16(len(typs)))
+				for i, typ := range typs {
+					desc = append(desc, fmt.Sprintf("c%d\x00", i)...)
+					desc = append(desc, 0, 0, 0, 0, 0, 0)
+					desc = binary.BigEndian.AppendUint32(desc, uint32(typ))
+					desc = append(desc, 0, 0, 0, 0, 0, 0, 0, 0)
+				}
+				send('T', desc)
+				send('C', []byte("SELECT 0\x00"))
+				send('Z', []byte{'T'})
+				continue
+			}
+			f := byte(0)
+			if binaryFormat {
+				f = 1
+			}
+			send('G', append([]byte{f}, binary.BigEndian.AppendUint16(nil, 0)...))
+		case 'c':
+			send('C', []byte("COPY 0\x00"))
+			send('Z', []byte{'T'})
+		}
+	}
+}
+
+// benchmarkCopyIn按文本或二进制格式向24列的宽表COPY b.N行
+func benchmarkCopyIn(b *testing.B, binaryFormat bool) {
+	pg := pgOid.PgOid
+	kinds := []oid.Oid{pg.T_int8, pg.T_float8, pg.T_numeric, pg.T_timestamptz, pg.T_text, pg.T_bytea}
+	typs := make([]oid.Oid, 24)
+	row := make([]driver.Value, len(typs))
+	blob := make([]byte, 32)
+	for i := range typs {
+		typs[i] = kinds[i%len(kinds)]
+		switch typs[i] {
+		case pg.T_int8:
+			row[i] = int64(1234567890123)
+		case pg.T_float8:
+			row[i] = 3.14159265358979
+		case pg.T_numeric:
+			row[i] = decimal.RequireFromString("12345678.9012")
+		case pg.T_timestamptz:
+			row[i] = time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+		case pg.T_text:
+			row[i] = "proton-rds-sdk-go"
+		case pg.T_bytea:
+			row[i] = blob
+		}
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go copyServer(b, server, typs, binaryFormat)
+	cn := &conn{c: client, buf: bufio.NewReader(client), txnStatus: txnStatusIdleInTransaction, allOid: pg}
+	query := CopyIn("t_wide")
+	if binaryFormat {
+		query = CopyInBinary("t_wide")
+	}
+	st, err := cn.Prepare(query)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err = st.Exec(row); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if _, err = st.Exec(nil); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkCopyInText(b *testing.B) {
+	benchmarkCopyIn(b, false)
+}
+
+func BenchmarkCopyInBinary(b *testing.B) {
+	benchmarkCopyIn(b, true)
+}
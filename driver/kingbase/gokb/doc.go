@@ -45,6 +45,10 @@ libkci支持的连接参数gokb也均支持
   - sslcert - ssl证书的位置
   - sslkey - ssl秘钥的位置
   - sslrootcert - 根证书的位置
+  - database_mode - 数据库模式(oracle、mysql、sqlserver、pg)，指定后不再通过show database_mode查询。
+    连接PostgreSQL或openGauss时需指定为pg
+  - pg_catalog_oid - 为true时使用标准PostgreSQL pg_catalog的类型oid，连接PostgreSQL或openGauss时指定，
+    Kingbase的pg模式不需要指定
 
 sslmode的有效值:
 
@@ -73,6 +77,8 @@ sslmode的有效值:
 	}
 
 4.CopyIn内部调用COPY FROM，不能在显示事务之外进行COPY
+使用CopyInBinary时按二进制格式发送数据，预备时会查询目标列的类型，支持整数、浮点数、numeric、字符串、
+bytea、json/jsonb、日期时间和uuid类型的列，时间戳、numeric、bytea较多的宽表导入速度明显高于文本格式
 用法:
 
 	txn, err := db.Begin()
@@ -115,5 +121,27 @@ sslmode的有效值:
 
 单个监听器可以安全地用于并发程序，这意味着通常不需要再应用程序中创建多个监听器。
 但是监听器总是连接到单个数据库，因此需要为希望接收通知的每个数据库创建一个新的Listener实例
+
+6.CopyTo执行COPY TO STDOUT，将导出的数据直接写入io.Writer，不需要在事务中执行，需通过sql.Conn.Raw取得驱动连接
+用法:
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(dc any) error {
+		c := dc.(interface {
+			CopyTo(ctx context.Context, w io.Writer, query string) (kb.CopyStats, error)
+		})
+		stats, err := c.CopyTo(ctx, f, kb.CopyOut("users", kb.CopyCSV, "name", "age"))
+		log.Println(stats.Rows, stats.Bytes)
+		return err
+	})
+
+CopyFrom与之对应，执行COPY FROM STDIN，将从io.Reader中读取的数据直接发送给服务端:
+
+	n, err := c.CopyFrom(ctx, f, "COPY users (name, age) FROM STDIN WITH (FORMAT csv, HEADER true)")
 */
 package gokb
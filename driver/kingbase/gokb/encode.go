@@ -350,9 +350,10 @@ func appendEncodedText(parameterStatus *parameterStatus, buf []byte, x interface
 		value = append(buf, "\\N"...)
 		return
 	default:
-		errorf("encode: unknown type for %T", v)
+		// CheckNamedValue保留的int、decimal.Decimal、civil.Date等类型按参数的文本格式编码
+		value = appendEscapedText(buf, string(encode(parameterStatus, x, 0, nil)))
+		return
 	}
-	panic("not reached")
 }
 
 func appendEscapedText(buf []byte, text string) (value []byte) {
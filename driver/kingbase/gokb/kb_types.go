@@ -39,6 +39,8 @@ type Driver struct{}
 type parameterStatus struct {
 	// 与server_version_num相同格式的服务端版本, 无效时为0
 	serverVersion int
+	// 服务端发送的原始server_version
+	serverVersionString string
 
 	// 基于当前对话时区的时区值
 	currentLocation *time.Location
@@ -353,6 +355,9 @@ var (
 	errCopyToNotSupported         = errors.New("kb: COPY TO is not supported")
 	errCopyNotSupportedOutsideTxn = errors.New("kb: COPY is only allowed inside a transaction")
 	errCopyInProgress             = errors.New("kb: COPY in progress")
+	errCopyFromNotSupported       = errors.New("kb: COPY FROM is not supported by CopyTo")
+	errNotCopyTo                  = errors.New("kb: CopyTo requires a COPY ... TO STDOUT statement")
+	errNotCopyFrom                = errors.New("kb: CopyFrom requires a COPY ... FROM STDIN statement")
 )
 
 type copyin struct {
@@ -361,6 +366,9 @@ type copyin struct {
 	rowData chan []byte
 	done    chan bool
 
+	// 二进制格式时各列的编码函数，文本格式时为空
+	encoders []copyEncoder
+
 	closed bool
 
 	sync.Mutex // guards err
@@ -0,0 +1,333 @@
+// 标准 PostgreSQL（含 openGauss）pg_catalog 中的 oid 列表
+
+package pgOid
+
+import (
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase/gokb/oid"
+)
+
+var PgOid = oid.AllOid{
+	T_bool:             16,
+	T_bytea:            17,
+	T_char:             18,
+	T_name:             19,
+	T_int8:             20,
+	T_int2:             21,
+	T_int2vector:       22,
+	T_int4:             23,
+	T_regproc:          24,
+	T_text:             25,
+	T_oid:              26,
+	T_tid:              27,
+	T_xid:              28,
+	T_cid:              29,
+	T_oidvector:        30,
+	T_pg_ddl_command:   32,
+	T_pg_type:          71,
+	T_pg_attribute:     75,
+	T_pg_proc:          81,
+	T_pg_class:         83,
+	T_json:             114,
+	T_xml:              142,
+	T__xml:             143,
+	T_pg_node_tree:     194,
+	T__json:            199,
+	T_table_am_handler: 269,
+	T_index_am_handler: 325,
+	T_point:            600,
+	T_lseg:             601,
+	T_path:             602,
+	T_box:              603,
+	T_polygon:          604,
+	T_line:             628,
+	T__line:            629,
+	T_cidr:             650,
+	T__cidr:            651,
+	T_float4:           700,
+	T_float8:           701,
+	T_unknown:          705,
+	T_circle:           718,
+	T__circle:          719,
+	T_macaddr8:         774,
+	T__macaddr8:        775,
+	T_money:            790,
+	T__money:           791,
+	T_macaddr:          829,
+	T_inet:             869,
+	T__bool:            1000,
+	T__bytea:           1001,
+	T__char:            1002,
+	T__name:            1003,
+	T__int2:            1005,
+	T__int2vector:      1006,
+	T__int4:            1007,
+	T__regproc:         1008,
+	T__text:            1009,
+	T__tid:             1010,
+	T__xid:             1011,
+	T__cid:             1012,
+	T__oidvector:       1013,
+	T__bpchar:          1014,
+	T__varchar:         1015,
+	T__int8:            1016,
+	T__point:           1017,
+	T__lseg:            1018,
+	T__path:            1019,
+	T__box:             1020,
+	T__float4:          1021,
+	T__float8:          1022,
+	T__polygon:         1027,
+	T__oid:             1028,
+	T_aclitem:          1033,
+	T__aclitem:         1034,
+	T__macaddr:         1040,
+	T__inet:            1041,
+	T_bpchar:           1042,
+	T_varchar:          1043,
+	T_date:             1082,
+	T_time:             1083,
+	T_timestamp:        1114,
+	T__timestamp:       1115,
+	T__date:            1182,
+	T__time:            1183,
+	T_timestamptz:      1184,
+	T__timestamptz:     1185,
+	T_interval:         1186,
+	T__interval:        1187,
+	T__numeric:         1231,
+	T__cstring:         1263,
+	T_timetz:           1266,
+	T__timetz:          1270,
+	T_bit:              1560,
+	T__bit:             1561,
+	T_varbit:           1562,
+	T__varbit:          1563,
+	T_numeric:          1700,
+	T_refcursor:        1790,
+	T__refcursor:       2201,
+	T_regprocedure:     2202,
+	T_regoper:          2203,
+	T_regoperator:      2204,
+	T_regclass:         2205,
+	T_regtype:          2206,
+	T__regprocedure:    2207,
+	T__regoper:         2208,
+	T__regoperator:     2209,
+	T__regclass:        2210,
+	T__regtype:         2211,
+	T_record:           2249,
+	T_cstring:          2275,
+	T_any:              2276,
+	T_anyarray:         2277,
+	T_void:             2278,
+	T_trigger:          2279,
+	T_language_handler: 2280,
+	T_internal:         2281,
+	T_anyelement:       2283,
+	T__record:          2287,
+	T_anynonarray:      2776,
+	T_uuid:             2950,
+	T__uuid:            2951,
+	T_txid_snapshot:    2970,
+	T__txid_snapshot:   2949,
+	T_fdw_handler:      3115,
+	T_pg_lsn:           3220,
+	T__pg_lsn:          3221,
+	T_tsm_handler:      3310,
+	T_anyenum:          3500,
+	T_tsvector:         3614,
+	T_tsquery:          3615,
+	T_gtsvector:        3642,
+	T__tsvector:        3643,
+	T__gtsvector:       3644,
+	T__tsquery:         3645,
+	T_regconfig:        3734,
+	T__regconfig:       3735,
+	T_regdictionary:    3769,
+	T__regdictionary:   3770,
+	T_jsonb:            3802,
+	T__jsonb:           3807,
+	T_anyrange:         3831,
+	T_event_trigger:    3838,
+	T_int4range:        3904,
+	T__int4range:       3905,
+	T_numrange:         3906,
+	T__numrange:        3907,
+	T_tsrange:          3908,
+	T__tsrange:         3909,
+	T_tstzrange:        3910,
+	T__tstzrange:       3911,
+	T_daterange:        3912,
+	T__daterange:       3913,
+	T_int8range:        3926,
+	T__int8range:       3927,
+	T_jsonpath:         4072,
+	T__jsonpath:        4073,
+	T_regnamespace:     4089,
+	T__regnamespace:    4090,
+	T_regrole:          4096,
+	T__regrole:         4097,
+}
+
+var TypeName = map[oid.Oid]string{
+	PgOid.T_bool:             "BOOL",
+	PgOid.T_bytea:            "BYTEA",
+	PgOid.T_char:             "CHAR",
+	PgOid.T_name:             "NAME",
+	PgOid.T_int8:             "INT8",
+	PgOid.T_int2:             "INT2",
+	PgOid.T_int2vector:       "INT2VECTOR",
+	PgOid.T_int4:             "INT4",
+	PgOid.T_regproc:          "REGPROC",
+	PgOid.T_text:             "TEXT",
+	PgOid.T_oid:              "OID",
+	PgOid.T_tid:              "TID",
+	PgOid.T_xid:              "XID",
+	PgOid.T_cid:              "CID",
+	PgOid.T_oidvector:        "OIDVECTOR",
+	PgOid.T_pg_ddl_command:   "PG_DDL_COMMAND",
+	PgOid.T_pg_type:          "PG_TYPE",
+	PgOid.T_pg_attribute:     "PG_ATTRIBUTE",
+	PgOid.T_pg_proc:          "PG_PROC",
+	PgOid.T_pg_class:         "PG_CLASS",
+	PgOid.T_json:             "JSON",
+	PgOid.T_xml:              "XML",
+	PgOid.T__xml:             "_XML",
+	PgOid.T_pg_node_tree:     "PG_NODE_TREE",
+	PgOid.T__json:            "_JSON",
+	PgOid.T_table_am_handler: "TABLE_AM_HANDLER",
+	PgOid.T_index_am_handler: "INDEX_AM_HANDLER",
+	PgOid.T_point:            "POINT",
+	PgOid.T_lseg:             "LSEG",
+	PgOid.T_path:             "PATH",
+	PgOid.T_box:              "BOX",
+	PgOid.T_polygon:          "POLYGON",
+	PgOid.T_line:             "LINE",
+	PgOid.T__line:            "_LINE",
+	PgOid.T_cidr:             "CIDR",
+	PgOid.T__cidr:            "_CIDR",
+	PgOid.T_float4:           "FLOAT4",
+	PgOid.T_float8:           "FLOAT8",
+	PgOid.T_unknown:          "UNKNOWN",
+	PgOid.T_circle:           "CIRCLE",
+	PgOid.T__circle:          "_CIRCLE",
+	PgOid.T_macaddr8:         "MACADDR8",
+	PgOid.T__macaddr8:        "_MACADDR8",
+	PgOid.T_money:            "MONEY",
+	PgOid.T__money:           "_MONEY",
+	PgOid.T_macaddr:          "MACADDR",
+	PgOid.T_inet:             "INET",
+	PgOid.T__bool:            "_BOOL",
+	PgOid.T__bytea:           "_BYTEA",
+	PgOid.T__char:            "_CHAR",
+	PgOid.T__name:            "_NAME",
+	PgOid.T__int2:            "_INT2",
+	PgOid.T__int2vector:      "_INT2VECTOR",
+	PgOid.T__int4:            "_INT4",
+	PgOid.T__regproc:         "_REGPROC",
+	PgOid.T__text:            "_TEXT",
+	PgOid.T__tid:             "_TID",
+	PgOid.T__xid:             "_XID",
+	PgOid.T__cid:             "_CID",
+	PgOid.T__oidvector:       "_OIDVECTOR",
+	PgOid.T__bpchar:          "_BPCHAR",
+	PgOid.T__varchar:         "_VARCHAR",
+	PgOid.T__int8:            "_INT8",
+	PgOid.T__point:           "_POINT",
+	PgOid.T__lseg:            "_LSEG",
+	PgOid.T__path:            "_PATH",
+	PgOid.T__box:             "_BOX",
+	PgOid.T__float4:          "_FLOAT4",
+	PgOid.T__float8:          "_FLOAT8",
+	PgOid.T__polygon:         "_POLYGON",
+	PgOid.T__oid:             "_OID",
+	PgOid.T_aclitem:          "ACLITEM",
+	PgOid.T__aclitem:         "_ACLITEM",
+	PgOid.T__macaddr:         "_MACADDR",
+	PgOid.T__inet:            "_INET",
+	PgOid.T_bpchar:           "BPCHAR",
+	PgOid.T_varchar:          "VARCHAR",
+	PgOid.T_date:             "DATE",
+	PgOid.T_time:             "TIME",
+	PgOid.T_timestamp:        "TIMESTAMP",
+	PgOid.T__timestamp:       "_TIMESTAMP",
+	PgOid.T__date:            "_DATE",
+	PgOid.T__time:            "_TIME",
+	PgOid.T_timestamptz:      "TIMESTAMPTZ",
+	PgOid.T__timestamptz:     "_TIMESTAMPTZ",
+	PgOid.T_interval:         "INTERVAL",
+	PgOid.T__interval:        "_INTERVAL",
+	PgOid.T__numeric:         "_NUMERIC",
+	PgOid.T__cstring:         "_CSTRING",
+	PgOid.T_timetz:           "TIMETZ",
+	PgOid.T__timetz:          "_TIMETZ",
+	PgOid.T_bit:              "BIT",
+	PgOid.T__bit:             "_BIT",
+	PgOid.T_varbit:           "VARBIT",
+	PgOid.T__varbit:          "_VARBIT",
+	PgOid.T_numeric:          "NUMERIC",
+	PgOid.T_refcursor:        "REFCURSOR",
+	PgOid.T__refcursor:       "_REFCURSOR",
+	PgOid.T_regprocedure:     "REGPROCEDURE",
+	PgOid.T_regoper:          "REGOPER",
+	PgOid.T_regoperator:      "REGOPERATOR",
+	PgOid.T_regclass:         "REGCLASS",
+	PgOid.T_regtype:          "REGTYPE",
+	PgOid.T__regprocedure:    "_REGPROCEDURE",
+	PgOid.T__regoper:         "_REGOPER",
+	PgOid.T__regoperator:     "_REGOPERATOR",
+	PgOid.T__regclass:        "_REGCLASS",
+	PgOid.T__regtype:         "_REGTYPE",
+	PgOid.T_record:           "RECORD",
+	PgOid.T_cstring:          "CSTRING",
+	PgOid.T_any:              "ANY",
+	PgOid.T_anyarray:         "ANYARRAY",
+	PgOid.T_void:             "VOID",
+	PgOid.T_trigger:          "TRIGGER",
+	PgOid.T_language_handler: "LANGUAGE_HANDLER",
+	PgOid.T_internal:         "INTERNAL",
+	PgOid.T_anyelement:       "ANYELEMENT",
+	PgOid.T__record:          "_RECORD",
+	PgOid.T_anynonarray:      "ANYNONARRAY",
+	PgOid.T_uuid:             "UUID",
+	PgOid.T__uuid:            "_UUID",
+	PgOid.T_txid_snapshot:    "TXID_SNAPSHOT",
+	PgOid.T__txid_snapshot:   "_TXID_SNAPSHOT",
+	PgOid.T_fdw_handler:      "FDW_HANDLER",
+	PgOid.T_pg_lsn:           "PG_LSN",
+	PgOid.T__pg_lsn:          "_PG_LSN",
+	PgOid.T_tsm_handler:      "TSM_HANDLER",
+	PgOid.T_anyenum:          "ANYENUM",
+	PgOid.T_tsvector:         "TSVECTOR",
+	PgOid.T_tsquery:          "TSQUERY",
+	PgOid.T_gtsvector:        "GTSVECTOR",
+	PgOid.T__tsvector:        "_TSVECTOR",
+	PgOid.T__gtsvector:       "_GTSVECTOR",
+	PgOid.T__tsquery:         "_TSQUERY",
+	PgOid.T_regconfig:        "REGCONFIG",
+	PgOid.T__regconfig:       "_REGCONFIG",
+	PgOid.T_regdictionary:    "REGDICTIONARY",
+	PgOid.T__regdictionary:   "_REGDICTIONARY",
+	PgOid.T_jsonb:            "JSONB",
+	PgOid.T__jsonb:           "_JSONB",
+	PgOid.T_anyrange:         "ANYRANGE",
+	PgOid.T_event_trigger:    "EVENT_TRIGGER",
+	PgOid.T_int4range:        "INT4RANGE",
+	PgOid.T__int4range:       "_INT4RANGE",
+	PgOid.T_numrange:         "NUMRANGE",
+	PgOid.T__numrange:        "_NUMRANGE",
+	PgOid.T_tsrange:          "TSRANGE",
+	PgOid.T__tsrange:         "_TSRANGE",
+	PgOid.T_tstzrange:        "TSTZRANGE",
+	PgOid.T__tstzrange:       "_TSTZRANGE",
+	PgOid.T_daterange:        "DATERANGE",
+	PgOid.T__daterange:       "_DATERANGE",
+	PgOid.T_int8range:        "INT8RANGE",
+	PgOid.T__int8range:       "_INT8RANGE",
+	PgOid.T_jsonpath:         "JSONPATH",
+	PgOid.T__jsonpath:        "_JSONPATH",
+	PgOid.T_regnamespace:     "REGNAMESPACE",
+	PgOid.T__regnamespace:    "_REGNAMESPACE",
+	PgOid.T_regrole:          "REGROLE",
+	PgOid.T__regrole:         "_REGROLE",
+}
@@ -0,0 +1,56 @@
+package oceanbase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// timeoutVars 为单位是微秒的 OceanBase 会话变量，DSN 中可以写成 Go 的时长格式，如 10s
+var timeoutVars = []string{"ob_query_timeout", "ob_trx_timeout", "ob_trx_idle_timeout"}
+
+// adjustConfig 处理 OceanBase 特有的 DSN 参数：
+//   - tenant、cluster 参数拼接为 user@tenant#cluster 格式的用户名，用户名中已包含 @ 时不处理
+//   - ob_query_timeout、ob_trx_timeout、ob_trx_idle_timeout 转换为微秒，连接建立后作为会话变量设置
+func adjustConfig(cfg *mysql.Config) error {
+	tenant, hasTenant := cfg.Params["tenant"]
+	cluster, hasCluster := cfg.Params["cluster"]
+	delete(cfg.Params, "tenant")
+	delete(cfg.Params, "cluster")
+	if !strings.Contains(cfg.User, "@") {
+		if hasTenant && tenant != "" {
+			cfg.User += "@" + tenant
+		}
+		if hasCluster && cluster != "" {
+			cfg.User += "#" + cluster
+		}
+	}
+
+	for _, name := range timeoutVars {
+		v, ok := cfg.Params[name]
+		if !ok {
+			continue
+		}
+		us, err := parseMicroseconds(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSN: invalid value for %s: %w", name, err)
+		}
+		cfg.Params[name] = strconv.FormatInt(us, 10)
+	}
+	return nil
+}
+
+// parseMicroseconds 解析整数形式的微秒数或 Go 的时长格式
+func parseMicroseconds(v string) (int64, error) {
+	if us, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return us, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	return d.Microseconds(), nil
+}
@@ -0,0 +1,74 @@
+package oceanbase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlError 为 OceanBase 错误码对应的 MySQL 错误码和 SQLSTATE
+type mysqlError struct {
+	number   uint16
+	sqlState string
+}
+
+// obErrors 为 MySQL 模式下 OceanBase 返回的内部错误码到 MySQL 错误码的映射，
+// 转换后重试和唯一键冲突判断与 MySQL 一致
+var obErrors = map[uint16]mysqlError{
+	4012: {3024, "HY000"}, // OB_TIMEOUT，超过 ob_query_timeout
+	4038: {1290, "HY000"}, // OB_NOT_MASTER，主副本切换中，按只读处理
+	5024: {1062, "23000"}, // OB_ERR_PRIMARY_KEY_DUPLICATE
+	6002: {1213, "40001"}, // OB_TRANS_ROLLBACKED，事务已回滚
+	6003: {1205, "HY000"}, // OB_TRANS_TIMEOUT，超过 ob_trx_timeout
+	6004: {3024, "HY000"}, // OB_TRANS_STMT_TIMEOUT
+	6005: {1205, "HY000"}, // OB_TRY_LOCK_ROW_CONFLICT，行锁冲突
+	6213: {1213, "40001"}, // OB_TRANS_KILLED
+	6224: {1213, "40001"}, // OB_TRANS_NEED_ROLLBACK
+}
+
+// Error 为转换了错误码的 OceanBase 错误，errors.As 取 *mysql.MySQLError 时得到转换后的 MySQL 错误，
+// 取 *Error 后可通过 Err 访问 OceanBase 的原始错误码和错误信息
+type Error struct {
+	// Mapped 为转换后的 MySQL 错误
+	Mapped *mysql.MySQLError
+	// Err 为 OceanBase 返回的原始错误
+	Err *mysql.MySQLError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (OceanBase error %d)", e.Mapped.Error(), e.Err.Number)
+}
+
+// Unwrap 返回 OceanBase 的原始错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// As 使 errors.As 取 *mysql.MySQLError 时得到转换后的错误，而不是 Unwrap 返回的原始错误
+func (e *Error) As(target any) bool {
+	if t, ok := target.(**mysql.MySQLError); ok {
+		*t = e.Mapped
+		return true
+	}
+	return false
+}
+
+// MapError 将 OceanBase 的内部错误码转换为对应的 MySQL 错误码，返回包装原始错误的 *Error，
+// 其它错误原样返回
+func MapError(err error) error {
+	var me *mysql.MySQLError
+	if !errors.As(err, &me) {
+		return err
+	}
+	m, ok := obErrors[me.Number]
+	if !ok {
+		return err
+	}
+	mapped := &mysql.MySQLError{
+		Number:  m.number,
+		Message: me.Message,
+	}
+	copy(mapped.SQLState[:], m.sqlState)
+	return &Error{Mapped: mapped, Err: me}
+}
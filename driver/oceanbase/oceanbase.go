@@ -0,0 +1,56 @@
+package oceanbase
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
+)
+
+func Open(dsn string) (driver.Conn, error) {
+	cnct, err := OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return cnct.Connect(context.Background())
+}
+
+func OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := common.ParseMySQLDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = adjustConfig(&cfg); err != nil {
+		return nil, err
+	}
+	cnct, err := mysql.NewConnector(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &OBCnct{cnct: cnct}, nil
+}
+
+// OBCnct 建立 MySQL 协议的连接，并将 OceanBase 的错误码转换为对应的 MySQL 错误码
+type OBCnct struct {
+	cnct driver.Connector
+}
+
+func (OCT *OBCnct) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := OCT.cnct.Connect(ctx)
+	if err != nil {
+		return nil, MapError(err)
+	}
+	return common.WrapConn(conn, MapError), nil
+}
+
+func (OCT *OBCnct) Driver() driver.Driver {
+	return Driver{}
+}
+
+type Driver struct{}
+
+func (d Driver) Open(name string) (driver.Conn, error) {
+	return Open(name)
+}
@@ -0,0 +1,78 @@
+package oceanbase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
+)
+
+func TestAdjustConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		dsn    string
+		user   string
+		params map[string]string
+	}{
+		{
+			name:   "tenant and cluster",
+			dsn:    "root:pwd@tcp(localhost:2883)/test?tenant=sys&cluster=obcluster&ob_query_timeout=10s",
+			user:   "root@sys#obcluster",
+			params: map[string]string{"ob_query_timeout": "10000000"},
+		},
+		{
+			name:   "user already qualified",
+			dsn:    "root@app#obcluster:pwd@tcp(localhost:2883)/test?tenant=sys&ob_trx_timeout=100000000",
+			user:   "root@app#obcluster",
+			params: map[string]string{"ob_trx_timeout": "100000000"},
+		},
+		{
+			name:   "tenant only",
+			dsn:    "root:pwd@tcp(localhost:2881)/test?tenant=app",
+			user:   "root@app",
+			params: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := common.ParseMySQLDSN(tt.dsn)
+			assert.Nil(t, err)
+			assert.Nil(t, adjustConfig(&cfg))
+			assert.Equal(t, tt.user, cfg.User)
+			if len(tt.params) == 0 {
+				assert.Empty(t, cfg.Params)
+			} else {
+				assert.Equal(t, tt.params, cfg.Params)
+			}
+		})
+	}
+
+	cfg, err := common.ParseMySQLDSN("root:pwd@tcp(localhost:2881)/test?ob_query_timeout=10xs")
+	assert.Nil(t, err)
+	assert.NotNil(t, adjustConfig(&cfg))
+}
+
+func TestMapError(t *testing.T) {
+	ob := &mysql.MySQLError{Number: 5024, Message: "Duplicate entry '1' for key 'PRIMARY'"}
+	err := MapError(ob)
+	var me *mysql.MySQLError
+	assert.True(t, errors.As(err, &me))
+	assert.Equal(t, uint16(1062), me.Number)
+	assert.Equal(t, "23000", string(me.SQLState[:]))
+	assert.Equal(t, "Error 1062 (23000): Duplicate entry '1' for key 'PRIMARY' (OceanBase error 5024)", err.Error())
+
+	// 原始错误仍可取出
+	var oe *Error
+	assert.True(t, errors.As(err, &oe))
+	assert.Equal(t, uint16(5024), oe.Err.Number)
+	assert.ErrorIs(t, err, ob)
+
+	orig := &mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}
+	assert.Equal(t, orig, MapError(orig))
+
+	other := errors.New("other")
+	assert.Equal(t, other, MapError(other))
+}
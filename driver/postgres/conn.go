@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
+)
+
+// PGConn 将 MySQL 风格的反引号标识符转换为双引号后交给 gokb 执行
+type PGConn struct {
+	conn driver.Conn
+}
+
+func (PC PGConn) ExecContext(ctx context.Context, sql string, args []driver.NamedValue) (driver.Result, error) {
+	return PC.conn.(driver.ExecerContext).ExecContext(ctx, common.ReplaceBackticks(sql), args)
+}
+
+func (PC PGConn) QueryContext(ctx context.Context, sql string, args []driver.NamedValue) (driver.Rows, error) {
+	return PC.conn.(driver.QueryerContext).QueryContext(ctx, common.ReplaceBackticks(sql), args)
+}
+
+func (PC PGConn) PrepareContext(ctx context.Context, sql string) (driver.Stmt, error) {
+	return PC.conn.Prepare(common.ReplaceBackticks(sql))
+}
+
+func (PC PGConn) Prepare(sql string) (driver.Stmt, error) {
+	return PC.conn.Prepare(common.ReplaceBackticks(sql))
+}
+
+func (PC PGConn) Begin() (driver.Tx, error) {
+	return PC.conn.Begin()
+}
+
+func (PC PGConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return PC.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (PC PGConn) Ping(ctx context.Context) error {
+	return PC.conn.(driver.Pinger).Ping(ctx)
+}
+
+func (PC PGConn) CheckNamedValue(nv *driver.NamedValue) error {
+	return PC.conn.(driver.NamedValueChecker).CheckNamedValue(nv)
+}
+
+func (PC PGConn) Close() error {
+	return PC.conn.Close()
+}
+
+// Unwrap 返回 gokb 的连接
+func (PC PGConn) Unwrap() driver.Conn {
+	return PC.conn
+}
+
+type PGCnct struct {
+	cnct driver.Connector
+}
+
+func (PCT *PGCnct) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := PCT.cnct.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return PGConn{conn: conn}, err
+}
+
+func (PCT *PGCnct) Driver() driver.Driver {
+	return Driver{}
+}
+
+type Driver struct{}
+
+func (d Driver) Open(name string) (driver.Conn, error) {
+	return Open(name)
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// passthroughParams 为原样传给服务端的 DSN 参数
+var passthroughParams = []string{"sslmode", "search_path", "application_name"}
+
+// FormatDSN 将 MySQL 风格的 DSN 配置转换为 gokb 的连接串，数据库名对应 dbname，
+// 并指定 database_mode=pg 和 pg_catalog_oid=true，不再查询 show database_mode，类型 oid 使用标准的 pg_catalog
+func FormatDSN(cfg mysql.Config) string {
+	dsn := ""
+	if cfg.User != "" {
+		dsn += fmt.Sprintf("user=%s ", quote(cfg.User))
+	}
+	if cfg.Passwd != "" {
+		dsn += fmt.Sprintf("password=%s ", quote(cfg.Passwd))
+	}
+	if cfg.Addr != "" {
+		s := strings.Split(cfg.Addr, ":")
+		port := s[len(s)-1]
+		host := cfg.Addr[:len(cfg.Addr)-len(port)-1]
+		dsn += fmt.Sprintf("host=%s port=%s ", strings.Trim(host, "[]"), port)
+	}
+	dbname := cfg.DBName
+	if dbname == "" {
+		dbname = "postgres"
+	}
+	dsn += fmt.Sprintf("dbname=%s ", quote(dbname))
+	dsn += fmt.Sprintf("connect_timeout=%d ", cfg.Timeout/(1000*1000*1000))
+	if _, ok := cfg.Params["sslmode"]; !ok {
+		dsn += "sslmode=disable "
+	}
+	for _, k := range passthroughParams {
+		if v, ok := cfg.Params[k]; ok {
+			dsn += fmt.Sprintf("%s=%s ", k, quote(v))
+		}
+	}
+	dsn += "database_mode=pg pg_catalog_oid=true"
+	return dsn
+}
+
+// quote 在值包含空格、单引号或反斜杠时按 gokb 连接串的规则加单引号并转义
+func quote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n'\\") {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
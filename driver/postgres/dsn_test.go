@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
+)
+
+func TestFormatDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "case1",
+			args: "username:password@tcp(localhost:5432)/test?timeout=10s&readTimeout=10s",
+			want: "user=username password=password host=localhost port=5432 dbname=test connect_timeout=10 sslmode=disable database_mode=pg pg_catalog_oid=true",
+		},
+		{
+			name: "case2",
+			args: "username:it's secret@tcp(localhost:5432)/",
+			want: `user=username password='it\'s secret' host=localhost port=5432 dbname=postgres connect_timeout=0 sslmode=disable database_mode=pg pg_catalog_oid=true`,
+		},
+		{
+			name: "case3",
+			args: "username:password@tcp([::1]:5432)/test?sslmode=require&search_path=app",
+			want: "user=username password=password host=::1 port=5432 dbname=test connect_timeout=0 sslmode=require search_path=app database_mode=pg pg_catalog_oid=true",
+		},
+	}
+	for _, tt := range tests {
+		cfg, err := common.ParseMySQLDSN(tt.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDSN(cfg); got != tt.want {
+				t.Errorf("FormatDSN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,59 +1,176 @@
 package driver
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"io"
 	"os"
 	"strings"
 
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/common"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/dmdb"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/goldendb"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/kingbase"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/mysql"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/oceanbase"
+	"github.com/kweaver-ai/proton-rds-sdk-go/driver/postgres"
 	"github.com/kweaver-ai/proton-rds-sdk-go/driver/tidb"
 )
 
+// DriverName 为按 DSN 参数或 DB_TYPE 环境变量选择数据库类型的驱动名
+const DriverName = "proton-rds"
+
+// dbTypeParam 为 DSN 中指定数据库类型的参数名，转发给具体驱动前会被去掉
+const dbTypeParam = "dbtype"
+
+// defaultBackend 为未指定数据库类型时使用的数据库类型
+const defaultBackend = "MYSQL"
+
+// RDSDriver 按连接选择具体的数据库驱动，选择顺序为：
+// DSN 中的 dbtype 参数、驱动名绑定的数据库类型（如 proton-rds-dm8）、DB_TYPE 环境变量
+// 数据库类型为 auto 时根据服务端的握手报文自动识别
 type RDSDriver struct {
+	dbType string
+	// pool 为连接器持有的连接池级别缓存，sql.Register 注册的驱动上为空
+	pool *poolCache
 }
 
-var supportedOpen = map[string]func(string) (driver.Conn, error){
-	"MYSQL":    mysql.Open,
-	"MARIADB":  mysql.Open,
-	"GOLDENDB": goldendb.Open,
-	"DM8":      dmdb.Open,
-	"TIDB":     tidb.Open,
-	"DEFAULT":  mysql.Open,
-	"KDB9":     kingbase.Open,
+func (d RDSDriver) Open(dsn string) (driver.Conn, error) {
+	b, dsn, err := d.backend(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return wrapConn(b.Open(dsn))
 }
 
-var supportedOpenConnector = map[string]func(string) (driver.Connector, error){
-	"MYSQL":    mysql.OpenConnector,
-	"MARIADB":  mysql.OpenConnector,
-	"GOLDENDB": goldendb.OpenConnector,
-	"DM8":      dmdb.OpenConnector,
-	"TIDB":     tidb.OpenConnector,
-	"DEFAULT":  mysql.OpenConnector,
-	"KDB9":     kingbase.OpenConnector,
+func (d RDSDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	if dbType, rest := d.resolve(dsn); dbType == AutoDBType {
+		return &autoConnector{dsn: rest}, nil
+	}
+	b, dsn, err := d.backend(dsn)
+	if err != nil {
+		return nil, err
+	}
+	c, err := b.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &rdsConnector{Connector: c, dbType: b.Name, pool: &poolCache{}}, nil
 }
 
-func (d RDSDriver) Open(dsn string) (driver.Conn, error) {
-	dbType := os.Getenv("DB_TYPE")
-	dbType = strings.ToUpper(dbType)
-	if v, ok := supportedOpen[dbType]; ok {
-		return v(dsn)
+// rdsConnector 记录连接器对应的数据库类型和连接池级别的缓存，sql.DB.Driver() 返回绑定二者的 RDSDriver，
+// 供 DBTypeOf、ServerInfo 使用
+type rdsConnector struct {
+	driver.Connector
+	dbType string
+	pool   *poolCache
+}
+
+func (c *rdsConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return wrapConn(c.Connector.Connect(ctx))
+}
+
+func (c *rdsConnector) Driver() driver.Driver {
+	return RDSDriver{dbType: c.dbType, pool: c.pool}
+}
+
+func (c *rdsConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
 	}
-	return supportedOpen["DEFAULT"](dsn)
+	return nil
 }
 
-func (d RDSDriver) OpenConnector(dsn string) (driver.Connector, error) {
-	dbType := os.Getenv("DB_TYPE")
-	dbType = strings.ToUpper(dbType)
-	if v, ok := supportedOpenConnector[dbType]; ok {
-		return v(dsn)
+// backend 返回本次连接使用的数据库类型和去掉 dbtype 参数后的 DSN
+// 未注册的数据库类型返回 *UnsupportedDBTypeError，开启宽松模式时退回到默认类型
+func (d RDSDriver) backend(dsn string) (Backend, string, error) {
+	dbType, dsn := d.resolve(dsn)
+	if dbType == "" || dbType == "DEFAULT" {
+		dbType = defaultBackend
+	}
+	if dbType == AutoDBType {
+		b, err := detectBackend(context.Background(), dsn)
+		return b, dsn, err
+	}
+	if b, ok := LookupBackend(dbType); ok {
+		return b, dsn, nil
+	}
+	if !lenient() {
+		return Backend{}, dsn, &UnsupportedDBTypeError{DBType: dbType, Supported: Backends()}
+	}
+	b, _ := LookupBackend(defaultBackend)
+	return b, dsn, nil
+}
+
+// wrapConn 在 SetWrapErrors 开启时包装具体驱动的连接，语句返回的错误转换为 *Error，可通过 errors.Is 判断类别
+func wrapConn(conn driver.Conn, err error) (driver.Conn, error) {
+	if !wrapErrorsEnabled() {
+		return conn, err
+	}
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	return common.WrapConn(conn, classifyError), nil
+}
+
+// UnwrapConn 返回具体驱动的连接，用于在 sql.Conn.Raw 中访问具体驱动的方法，连接未被包装时原样返回
+func UnwrapConn(dc any) any {
+	for {
+		u, ok := dc.(interface{ Unwrap() driver.Conn })
+		if !ok {
+			return dc
+		}
+		dc = u.Unwrap()
+	}
+}
+
+// resolve 返回本次连接使用的数据库类型和去掉 dbtype 参数后的 DSN
+func (d RDSDriver) resolve(dsn string) (string, string) {
+	dbType, dsn := SplitDBType(dsn)
+	if dbType == "" {
+		dbType = d.dbType
+	}
+	if dbType == "" {
+		dbType = os.Getenv("DB_TYPE")
+	}
+	return NormalizeDBType(dbType), dsn
+}
+
+// SplitDBType 从 DSN 中取出 dbtype 参数，返回参数值和去掉该参数后的 DSN
+// DSN 中没有 dbtype 参数时返回空字符串和原 DSN
+func SplitDBType(dsn string) (dbType string, rest string) {
+	slash := strings.LastIndexByte(dsn, '/')
+	question := strings.IndexByte(dsn[slash+1:], '?')
+	if question < 0 {
+		return "", dsn
+	}
+	question += slash + 1
+
+	params := strings.Split(dsn[question+1:], "&")
+	kept := params[:0]
+	for _, param := range params {
+		if k, v, ok := strings.Cut(param, "="); ok && k == dbTypeParam {
+			dbType = v
+			continue
+		}
+		kept = append(kept, param)
+	}
+	if len(kept) == 0 {
+		return dbType, dsn[:question]
 	}
-	return supportedOpenConnector["DEFAULT"](dsn)
+	return dbType, dsn[:question+1] + strings.Join(kept, "&")
 }
 
 func init() {
-	sql.Register("proton-rds", &RDSDriver{})
+	sql.Register(DriverName, &RDSDriver{})
+	mustRegisterBackend("MYSQL", mysql.Open, mysql.OpenConnector)
+	mustRegisterBackend("MARIADB", mysql.Open, mysql.OpenConnector)
+	mustRegisterBackend("GOLDENDB", goldendb.Open, goldendb.OpenConnector)
+	mustRegisterBackend("DM8", dmdb.Open, dmdb.OpenConnector)
+	mustRegisterBackend("TIDB", tidb.Open, tidb.OpenConnector)
+	mustRegisterBackend("KDB9", kingbase.Open, kingbase.OpenConnector)
+	mustRegisterBackend("POSTGRES", postgres.Open, postgres.OpenConnector)
+	mustRegisterBackend("OPENGAUSS", postgres.Open, postgres.OpenConnector)
+	mustRegisterBackend("OCEANBASE", oceanbase.Open, oceanbase.OpenConnector)
 }
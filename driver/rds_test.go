@@ -231,3 +231,101 @@ func TestOpenConnector3(t *testing.T) {
 		})
 	})
 }
+
+func TestSplitDBType(t *testing.T) {
+	tests := []struct {
+		name   string
+		dsn    string
+		dbType string
+		rest   string
+	}{
+		{
+			name:   "no params",
+			dsn:    "user:pwd@tcp(localhost:3306)/test",
+			dbType: "",
+			rest:   "user:pwd@tcp(localhost:3306)/test",
+		},
+		{
+			name:   "only dbtype",
+			dsn:    "user:pwd@tcp(localhost:5236)/test?dbtype=dm8",
+			dbType: "dm8",
+			rest:   "user:pwd@tcp(localhost:5236)/test",
+		},
+		{
+			name:   "dbtype among params",
+			dsn:    "user:pwd@tcp(localhost:54321)/test?timeout=10s&dbtype=KDB9&readTimeout=10s",
+			dbType: "KDB9",
+			rest:   "user:pwd@tcp(localhost:54321)/test?timeout=10s&readTimeout=10s",
+		},
+		{
+			name:   "question mark in password",
+			dsn:    "user:p?wd@tcp(localhost:3306)/test?timeout=10s",
+			dbType: "",
+			rest:   "user:p?wd@tcp(localhost:3306)/test?timeout=10s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbType, rest := SplitDBType(tt.dsn)
+			assert.Equal(t, tt.dbType, dbType)
+			assert.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestResolveDBType(t *testing.T) {
+	t.Setenv("DB_TYPE", "mysql")
+	dsn := "user:pwd@tcp(localhost:3306)/test?timeout=10s"
+
+	dbType, _ := RDSDriver{}.resolve(dsn)
+	assert.Equal(t, "MYSQL", dbType)
+
+	dbType, _ = RDSDriver{dbType: "DM8"}.resolve(dsn)
+	assert.Equal(t, "DM8", dbType)
+
+	dbType, rest := RDSDriver{dbType: "DM8"}.resolve(dsn + "&dbtype=kdb9")
+	assert.Equal(t, "KDB9", dbType)
+	assert.Equal(t, dsn, rest)
+}
+
+func TestNormalizeDBType(t *testing.T) {
+	for in, want := range map[string]string{
+		"kingbase": "KDB9",
+		"KES":      "KDB9",
+		"kdb":      "KDB9",
+		"kdb9":     "KDB9",
+		"dameng":   "DM8",
+		" dm ":     "DM8",
+		"mysql":    "MYSQL",
+		"ob":       "OCEANBASE",
+		"":         "",
+	} {
+		assert.Equal(t, want, NormalizeDBType(in), in)
+	}
+}
+
+func TestStrictBackend(t *testing.T) {
+	dsn := "user:pwd@tcp(localhost:3306)/test"
+
+	_, _, err := RDSDriver{dbType: "ORACLE"}.backend(dsn)
+	var unsupported *UnsupportedDBTypeError
+	assert.ErrorAs(t, err, &unsupported)
+	assert.ErrorIs(t, err, ErrUnsupportedDBType)
+	assert.Equal(t, "ORACLE", unsupported.DBType)
+	assert.Contains(t, unsupported.Supported, "DM8")
+
+	b, _, err := RDSDriver{dbType: "dameng"}.backend(dsn)
+	assert.Nil(t, err)
+	assert.Equal(t, "DM8", b.Name)
+
+	t.Setenv("DB_TYPE", "")
+	b, _, err = RDSDriver{}.backend(dsn)
+	assert.Nil(t, err)
+	assert.Equal(t, "MYSQL", b.Name)
+
+	SetLenientDBType(true)
+	defer SetLenientDBType(false)
+	b, _, err = RDSDriver{dbType: "ORACLE"}.backend(dsn)
+	assert.Nil(t, err)
+	assert.Equal(t, "MYSQL", b.Name)
+}
@@ -0,0 +1,110 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Opener 按 DSN 打开一个到数据库的新连接
+type Opener func(dsn string) (driver.Conn, error)
+
+// ConnectorOpener 按 DSN 创建一个连接器
+type ConnectorOpener func(dsn string) (driver.Connector, error)
+
+// Backend 为一种已注册的数据库类型
+type Backend struct {
+	// Name 为大写的数据库类型名，即 DB_TYPE 和 dbtype 参数的取值
+	Name          string
+	Open          Opener
+	OpenConnector ConnectorOpener
+}
+
+var (
+	ErrBackendExists  = errors.New("proton-rds: backend already registered")
+	ErrInvalidBackend = errors.New("proton-rds: invalid backend")
+)
+
+var backends = struct {
+	sync.RWMutex
+	m map[string]Backend
+}{m: make(map[string]Backend)}
+
+// RegisterBackend 注册一种数据库类型，注册后可通过 DB_TYPE、DSN 的 dbtype 参数
+// 或驱动名 proton-rds-<name> 使用。name 不区分大小写，重复注册返回 ErrBackendExists
+// connectorOpener 为空时每次 Connect 都调用 open 创建连接
+func RegisterBackend(name string, open Opener, connectorOpener ConnectorOpener) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" || name == "DEFAULT" || open == nil {
+		return fmt.Errorf("%w: %q", ErrInvalidBackend, name)
+	}
+	if connectorOpener == nil {
+		connectorOpener = func(dsn string) (driver.Connector, error) {
+			return &dsnConnector{dsn: dsn, name: name, open: open}, nil
+		}
+	}
+
+	driverName := DriverName + "-" + strings.ToLower(name)
+	backends.Lock()
+	if _, ok := backends.m[name]; ok {
+		backends.Unlock()
+		return fmt.Errorf("%w: %s", ErrBackendExists, name)
+	}
+	// sql.Register 在驱动名已被占用时 panic，先检查
+	if slices.Contains(sql.Drivers(), driverName) {
+		backends.Unlock()
+		return fmt.Errorf("%w: driver %s", ErrBackendExists, driverName)
+	}
+	backends.m[name] = Backend{Name: name, Open: open, OpenConnector: connectorOpener}
+	backends.Unlock()
+
+	sql.Register(driverName, &RDSDriver{dbType: name})
+	return nil
+}
+
+// LookupBackend 按名字查找已注册的数据库类型，name 不区分大小写
+func LookupBackend(name string) (Backend, bool) {
+	backends.RLock()
+	defer backends.RUnlock()
+	b, ok := backends.m[strings.ToUpper(name)]
+	return b, ok
+}
+
+// Backends 返回所有已注册的数据库类型名，按字母排序
+func Backends() []string {
+	backends.RLock()
+	defer backends.RUnlock()
+	names := make([]string, 0, len(backends.m))
+	for name := range backends.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mustRegisterBackend(name string, open Opener, connectorOpener ConnectorOpener) {
+	if err := RegisterBackend(name, open, connectorOpener); err != nil {
+		panic(err)
+	}
+}
+
+// dsnConnector 为未提供 ConnectorOpener 的数据库类型实现 driver.Connector
+type dsnConnector struct {
+	dsn  string
+	name string
+	open Opener
+}
+
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return RDSDriver{dbType: c.name}
+}
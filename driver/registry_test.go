@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestBackend = errors.New("test backend")
+
+func TestRegisterBackend(t *testing.T) {
+	var opened string
+	open := func(dsn string) (driver.Conn, error) {
+		opened = dsn
+		return nil, errTestBackend
+	}
+
+	assert.Nil(t, RegisterBackend("testdb", open, nil))
+	assert.ErrorIs(t, RegisterBackend("TESTDB", open, nil), ErrBackendExists)
+	assert.ErrorIs(t, RegisterBackend("", open, nil), ErrInvalidBackend)
+	assert.ErrorIs(t, RegisterBackend("other", nil, nil), ErrInvalidBackend)
+
+	// 驱动名已被其它包注册时返回错误而不是 panic
+	sql.Register("proton-rds-taken", RDSDriver{})
+	assert.ErrorIs(t, RegisterBackend("taken", open, nil), ErrBackendExists)
+	assert.NotContains(t, Backends(), "TAKEN")
+
+	b, ok := LookupBackend("TestDB")
+	assert.True(t, ok)
+	assert.Equal(t, "TESTDB", b.Name)
+	assert.Contains(t, Backends(), "TESTDB")
+	assert.Contains(t, Backends(), "KDB9")
+
+	db, err := sql.Open("proton-rds-testdb", "user:pwd@tcp(localhost:1)/test")
+	assert.Nil(t, err)
+	assert.ErrorIs(t, db.Ping(), errTestBackend)
+	assert.Equal(t, "user:pwd@tcp(localhost:1)/test", opened)
+
+	db, err = sql.Open("proton-rds", "user:pwd@tcp(localhost:2)/test?dbtype=testdb")
+	assert.Nil(t, err)
+	assert.ErrorIs(t, db.Ping(), errTestBackend)
+	assert.Equal(t, "user:pwd@tcp(localhost:2)/test", opened)
+}
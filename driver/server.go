@@ -0,0 +1,214 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Server 为服务端的产品信息
+type Server struct {
+	DBType string
+	// Product 为产品名，如 MySQL、MariaDB、TiDB、DM、KingbaseES、PostgreSQL、openGauss
+	Product string
+	// Version 为解析后的产品版本号，如 8.0.36、8.1.2.128（DM8）、8.6.8.14（KingbaseES V008R006C008B0014）
+	Version string
+	// RawVersion 为服务端返回的原始版本串
+	RawVersion string
+	// Edition 为产品版本类型，如 Community、Enterprise，DM 为 V$LICENSE 的 PRODUCT_TYPE，未知时为空
+	Edition string
+	// Mode 为兼容模式，DM 为 COMPATIBLE_MODE，Kingbase 为 database_mode，openGauss 为 sql_compatibility，
+	// 统一为小写的 oracle、mysql、pg、sqlserver 等
+	Mode string
+}
+
+// poolCache 为连接池级别的缓存，由连接器持有，通过 sql.DB.Driver() 返回的 RDSDriver 取得。
+// mu 只保护字段的读写，不能在持有时建立连接或查询，否则建立连接失败时 autoConnector.forget 会死锁
+type poolCache struct {
+	mu     sync.Mutex
+	server *Server
+	// gen 在丢弃缓存时递增，查询期间缓存被丢弃时不保存查询结果
+	gen int
+}
+
+// reset 丢弃缓存的服务端信息
+func (p *poolCache) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.server = nil
+	p.gen++
+}
+
+func poolCacheOf(db *sql.DB) *poolCache {
+	switch d := db.Driver().(type) {
+	case RDSDriver:
+		return d.pool
+	case *RDSDriver:
+		return d.pool
+	}
+	return nil
+}
+
+var products = map[string]string{
+	"MYSQL":     "MySQL",
+	"MARIADB":   "MariaDB",
+	"GOLDENDB":  "GoldenDB",
+	"TIDB":      "TiDB",
+	"OCEANBASE": "OceanBase",
+	"DM8":       "DM",
+	"KDB9":      "KingbaseES",
+	"POSTGRES":  "PostgreSQL",
+	"OPENGAUSS": "openGauss",
+	"SQLITE":    "SQLite",
+}
+
+// dmCompatibleModes 为 DM 的 COMPATIBLE_MODE 参数值对应的兼容模式
+var dmCompatibleModes = map[string]string{
+	"0": "dm",
+	"1": "sql92",
+	"2": "oracle",
+	"3": "sqlserver",
+	"4": "mysql",
+	"5": "dm6",
+	"6": "teradata",
+	"7": "pg",
+}
+
+// openGaussCompatibilities 为 openGauss 的 sql_compatibility 对应的兼容模式
+var openGaussCompatibilities = map[string]string{
+	"A":  "oracle",
+	"B":  "mysql",
+	"C":  "teradata",
+	"PG": "pg",
+}
+
+// ServerInfo 返回连接池所连服务端的产品、版本和兼容模式，通过 proton-rds 驱动打开的连接池只查询一次
+func ServerInfo(ctx context.Context, db *sql.DB) (Server, error) {
+	cache := poolCacheOf(db)
+	var gen int
+	if cache != nil {
+		cache.mu.Lock()
+		server := cache.server
+		gen = cache.gen
+		cache.mu.Unlock()
+		if server != nil {
+			return *server, nil
+		}
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return Server{}, err
+	}
+	defer conn.Close()
+	// 建立连接后才能确定 auto 的探测结果
+	s, err := queryServer(ctx, conn, DBTypeOf(db))
+	if err != nil {
+		return Server{}, err
+	}
+	if cache != nil {
+		cache.mu.Lock()
+		if cache.gen == gen {
+			cache.server = &s
+		}
+		cache.mu.Unlock()
+	}
+	return s, nil
+}
+
+// queryServer 通过 conn 查询服务端信息，只读取系统视图，可选信息查询失败时留空
+func queryServer(ctx context.Context, conn *sql.Conn, dbType string) (Server, error) {
+	var mode, paramVersion string
+	err := conn.Raw(func(dc any) error {
+		dc = UnwrapConn(dc)
+		if m, ok := dc.(interface{ DatabaseMode() string }); ok {
+			mode = m.DatabaseMode()
+		}
+		if m, ok := dc.(interface{ ServerVersion() string }); ok {
+			paramVersion = m.ServerVersion()
+		}
+		return nil
+	})
+	if err != nil {
+		return Server{}, err
+	}
+
+	queryString := func(query string) (string, error) {
+		var v sql.NullString
+		err := conn.QueryRowContext(ctx, query).Scan(&v)
+		return v.String, err
+	}
+	var raw, edition string
+	switch dbType {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		var comment sql.NullString
+		if err = conn.QueryRowContext(ctx, "SELECT VERSION(), @@version_comment").Scan(&raw, &comment); err != nil {
+			return Server{}, err
+		}
+		edition = mysqlEdition(raw, comment.String)
+		if dbType == "OCEANBASE" {
+			mode = "mysql"
+		}
+	case "DM8":
+		if raw, err = queryString("SELECT SVR_VERSION FROM V$INSTANCE"); err != nil {
+			return Server{}, err
+		}
+		edition, _ = queryString("SELECT PRODUCT_TYPE FROM V$LICENSE")
+		m, _ := queryString("SELECT PARA_VALUE FROM V$DM_INI WHERE PARA_NAME = 'COMPATIBLE_MODE'")
+		mode = dmCompatibleModes[m]
+	case "KDB9", "OPENGAUSS":
+		if raw, err = queryString("SELECT version()"); err != nil {
+			return Server{}, err
+		}
+		if dbType == "OPENGAUSS" {
+			m, _ := queryString("SHOW sql_compatibility")
+			mode = openGaussCompatibilities[strings.ToUpper(m)]
+		}
+	case "POSTGRES":
+		raw = paramVersion
+		if raw == "" {
+			if raw, err = queryString("SHOW server_version"); err != nil {
+				return Server{}, err
+			}
+		}
+		mode = "pg"
+	case "SQLITE":
+		if raw, err = queryString("SELECT sqlite_version()"); err != nil {
+			return Server{}, err
+		}
+	default:
+		// 通过 RegisterBackend 注册的其他数据库和非 proton-rds 驱动打开的连接池按 SQL 标准的 version() 查询
+		if raw, err = queryString("SELECT version()"); err != nil {
+			return Server{}, fmt.Errorf("%w %q: cannot query the server version: %w", ErrUnsupportedDBType, dbType, err)
+		}
+	}
+	return newServer(dbType, raw, edition, mode), nil
+}
+
+func newServer(dbType, raw, edition, mode string) Server {
+	return Server{
+		DBType:     dbType,
+		Product:    products[dbType],
+		Version:    parseVersion(dbType, raw).String(),
+		RawVersion: raw,
+		Edition:    strings.TrimSpace(edition),
+		Mode:       strings.ToLower(mode),
+	}
+}
+
+// mysqlEdition 从 VERSION() 和 @@version_comment 中识别社区版或企业版
+func mysqlEdition(version, comment string) string {
+	switch {
+	case strings.Contains(version, "OceanBase_CE"):
+		return "Community"
+	case strings.Contains(version, "OceanBase"):
+		return "Enterprise"
+	case strings.Contains(comment, "Enterprise"):
+		return "Enterprise"
+	case strings.Contains(comment, "Community"), strings.Contains(comment, "GPL"):
+		return "Community"
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	tests := []struct {
+		dbType  string
+		raw     string
+		comment string
+		product string
+		version string
+		edition string
+	}{
+		{"MYSQL", "8.0.36", "MySQL Community Server - GPL", "MySQL", "8.0.36", "Community"},
+		{"MYSQL", "8.0.36-commercial", "MySQL Enterprise Server - Commercial", "MySQL", "8.0.36", "Enterprise"},
+		{"MARIADB", "5.5.5-10.6.12-MariaDB", "mariadb.org binary distribution", "MariaDB", "10.6.12", ""},
+		{"TIDB", "5.7.25-TiDB-v7.1.0", "TiDB Server (Apache License 2.0) Community Edition, MySQL 5.7 compatible", "TiDB", "7.1.0", "Community"},
+		{"OCEANBASE", "5.7.25-OceanBase_CE-v4.2.1.0", "OceanBase_CE 4.2.1.0", "OceanBase", "4.2.1.0", "Community"},
+		{"KDB9", "KingbaseES V008R006C008B0014 on x86_64-pc-linux-gnu, compiled by gcc", "", "KingbaseES", "8.6.8.14", ""},
+		{"OPENGAUSS", "(openGauss 5.0.0 build a07d57c3) compiled at 2023-03-29", "", "openGauss", "5.0.0", ""},
+		{"DM8", "DM Database Server 64 V8", "", "DM", "8", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			s := newServer(tt.dbType, tt.raw, mysqlEdition(tt.raw, tt.comment), "")
+			assert.Equal(t, tt.product, s.Product)
+			assert.Equal(t, tt.version, s.Version)
+			assert.Equal(t, tt.edition, s.Edition)
+			assert.Equal(t, tt.raw, s.RawVersion)
+		})
+	}
+}
+
+func TestServerInfoFallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT version\(\)`).WillReturnError(errors.New("no such function"))
+	_, err = ServerInfo(context.Background(), db)
+	assert.ErrorIs(t, err, ErrUnsupportedDBType)
+
+	mock.ExpectQuery(`SELECT version\(\)`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("PostgreSQL 16.2"))
+	s, err := ServerInfo(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, "PostgreSQL 16.2", s.RawVersion)
+	assert.Equal(t, "16.2", s.Version)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestServerInfoConnectionLost(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			c.Write(mysqlGreeting("8.0.33"))
+			c.Close()
+		}
+	}()
+	auto := &autoConnector{dsn: "user:pwd@tcp(" + l.Addr().String() + ")/test"}
+	_, err = auto.resolve(context.Background())
+	assert.Nil(t, err)
+	l.Close()
+
+	// 建立连接失败时 forget 丢弃缓存，ServerInfo 不能持有缓存的锁建立连接
+	db := sql.OpenDB(auto)
+	defer db.Close()
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := ServerInfo(ctx, db)
+		done <- err
+	}()
+	select {
+	case err = <-done:
+		assert.Equal(t, ConnectionLost, Classify(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServerInfo blocked after the connection was lost")
+	}
+	assert.Equal(t, RDSDriver{dbType: AutoDBType}, db.Driver())
+}
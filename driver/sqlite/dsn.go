@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryDB 为内存数据库的数据库名
+const MemoryDB = ":memory:"
+
+// memorySeq 为内存数据库的序号，每次调用 FormatDSN 得到一个新的内存数据库
+var memorySeq atomic.Int64
+
+// FormatDSN 将 MySQL 风格的 DSN 转换为 go-sqlite3 的连接串，用户名、密码和地址被忽略：
+//   - 数据库名为空或 :memory: 时每次调用都使用一个新的具名内存数据库，以共享缓存打开，
+//     同一连接器（即连接池）的连接看到同一份数据，不同连接池之间互不影响
+//   - 其它数据库名为文件路径，地址后的部分整体作为路径，如 tcp(localhost:0)//tmp/test.db 为 /tmp/test.db
+//   - timeout 参数转换为 _busy_timeout，以下划线开头的参数和 mode、cache 原样传给 go-sqlite3，其它参数被忽略
+func FormatDSN(dsn string) (string, error) {
+	rest, rawQuery, _ := strings.Cut(dsn, "?")
+	// 地址中可能包含 /（如 unix socket），文件路径中也可能包含 /，以地址的右括号作为分隔
+	var name string
+	if i := strings.Index(rest, ")/"); i >= 0 {
+		name = rest[i+2:]
+	} else if i := strings.IndexByte(rest, '/'); i >= 0 {
+		name = rest[i+1:]
+	} else {
+		return "", fmt.Errorf("invalid DSN: missing the slash separating the database name")
+	}
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid DSN: %w", err)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid DSN: %w", err)
+	}
+
+	params := url.Values{}
+	for k, v := range query {
+		if strings.HasPrefix(k, "_") || k == "mode" || k == "cache" {
+			params[k] = v
+		}
+	}
+	if timeout := query.Get("timeout"); timeout != "" && params.Get("_busy_timeout") == "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return "", fmt.Errorf("invalid DSN: invalid value for timeout: %w", err)
+		}
+		params.Set("_busy_timeout", fmt.Sprint(d.Milliseconds()))
+	}
+
+	if name == "" || name == MemoryDB {
+		// 每个连接单独打开 :memory: 会得到不同的数据库，具名的内存数据库以共享缓存打开时连接池共用一份，
+		// file::memory: 为进程内全局共享，不同连接池会互相影响
+		name = "file:proton-rds-memory-" + strconv.FormatInt(memorySeq.Add(1), 10)
+		params.Set("mode", "memory")
+		params.Set("cache", "shared")
+	} else {
+		name = "file:" + name
+	}
+	if len(params) == 0 {
+		return name, nil
+	}
+	return name + "?" + encode(params), nil
+}
+
+// encode 按参数名排序编码，值中的 / 和 : 不转义
+func encode(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range params[k] {
+			s = append(s, k+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.Join(s, "&")
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFormatDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "memory",
+			args: "user:pwd@tcp(localhost:0)/:memory:?charset=utf8mb4&parseTime=true",
+			want: `^file:proton-rds-memory-\d+\?cache=shared&mode=memory$`,
+		},
+		{
+			name: "empty database name",
+			args: "user:pwd@tcp(localhost:0)/",
+			want: `^file:proton-rds-memory-\d+\?cache=shared&mode=memory$`,
+		},
+		{
+			name: "relative path",
+			args: "user:pwd@tcp(localhost:0)/test.db?timeout=10s",
+			want: "file:test.db?_busy_timeout=10000",
+		},
+		{
+			name: "absolute path",
+			args: "user:pwd@tcp(localhost:0)//tmp/rds/test.db?_foreign_keys=1&mode=rwc",
+			want: "file:/tmp/rds/test.db?_foreign_keys=1&mode=rwc",
+		},
+		{
+			name: "escaped path",
+			args: "user:pwd@/%2Ftmp%2Ftest.db",
+			want: "file:/tmp/test.db",
+		},
+		{
+			name:    "invalid timeout",
+			args:    "user:pwd@tcp(localhost:0)/test.db?timeout=10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatDSN(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatDSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want && !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("FormatDSN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 每次调用得到不同的内存数据库
+	a, _ := FormatDSN("user:pwd@tcp(localhost:0)/:memory:")
+	b, _ := FormatDSN("user:pwd@tcp(localhost:0)/:memory:")
+	if a == b {
+		t.Errorf("FormatDSN() returned the same memory database %v twice", a)
+	}
+}
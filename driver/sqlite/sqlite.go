@@ -0,0 +1,99 @@
+// Package sqlite 为基于 go-sqlite3 的 SQLite 数据库类型，用于本地开发和单元测试。
+// go-sqlite3 依赖 cgo，该包不被 driver 包导入，需要时以空白导入注册：
+//
+//	import _ "github.com/kweaver-ai/proton-rds-sdk-go/driver/sqlite"
+//
+// 注册后可通过 DB_TYPE=sqlite、DSN 的 dbtype=sqlite 参数或驱动名 proton-rds-sqlite 使用
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// SQLite 本身支持反引号标识符和 ? 占位符，MySQL 风格的 SQL 无需改写即可执行
+// go-sqlite3 依赖 cgo，CGO_ENABLED=0 编译时建立连接会返回错误
+
+func init() {
+	if err := rds.RegisterBackend("SQLITE", Open, OpenConnector); err != nil {
+		panic(err)
+	}
+	rds.RegisterClassifier(classify)
+}
+
+func Open(dsn string) (driver.Conn, error) {
+	name, err := FormatDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return open(name)
+}
+
+func OpenConnector(dsn string) (driver.Connector, error) {
+	name, err := FormatDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteCnct{name: name}, nil
+}
+
+// open 打开 go-sqlite3 的连接
+func open(name string) (driver.Conn, error) {
+	return (&sqlite3.SQLiteDriver{}).Open(name)
+}
+
+type SQLiteCnct struct {
+	name string
+}
+
+func (SCT *SQLiteCnct) Connect(ctx context.Context) (driver.Conn, error) {
+	return open(SCT.name)
+}
+
+func (SCT *SQLiteCnct) Driver() driver.Driver {
+	return Driver{}
+}
+
+type Driver struct{}
+
+func (d Driver) Open(name string) (driver.Conn, error) {
+	return Open(name)
+}
+
+// Classify 返回错误的类别，go-sqlite3 的错误已通过 rds.RegisterClassifier 注册，与 rds.Classify 的结果相同
+func Classify(err error) rds.Category {
+	return rds.Classify(err)
+}
+
+// classify 返回 go-sqlite3 错误的类别，其它错误返回 Unknown
+func classify(err error) rds.Category {
+	var se sqlite3.Error
+	if !errors.As(err, &se) {
+		return rds.Unknown
+	}
+	switch se.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return rds.UniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		return rds.ForeignKeyViolation
+	}
+	switch se.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return rds.LockTimeout
+	case sqlite3.ErrReadonly:
+		return rds.ReadOnly
+	case sqlite3.ErrPerm, sqlite3.ErrAuth:
+		return rds.PermissionDenied
+	case sqlite3.ErrError:
+		if strings.Contains(se.Error(), "syntax error") {
+			return rds.SyntaxError
+		}
+	}
+	return rds.Unknown
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+func openMemory(t *testing.T) *sql.DB {
+	db, err := sql.Open(rds.DriverName, "user:pwd@tcp(localhost:0)/:memory:?dbtype=sqlite&timeout=5s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBackend(t *testing.T) {
+	db := openMemory(t)
+	db.SetMaxOpenConns(2)
+
+	_, err := db.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY, `f_name` VARCHAR(32))")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO `t_user` (`f_id`, `f_name`) VALUES (?, ?), (?, ?)", 1, "a", 2, "b")
+	assert.Nil(t, err)
+
+	// 连接池中的连接共用同一个内存数据库
+	tx, err := db.Begin()
+	assert.Nil(t, err)
+	var count int
+	assert.Nil(t, db.QueryRow("SELECT COUNT(*) FROM `t_user` WHERE `f_id` > ?", 0).Scan(&count))
+	assert.Equal(t, 2, count)
+	assert.Nil(t, tx.Rollback())
+
+	// 不同连接池的内存数据库互不影响
+	other := openMemory(t)
+	_, err = other.Exec("SELECT COUNT(*) FROM `t_user`")
+	assert.NotNil(t, err)
+	_, err = other.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY)")
+	assert.Nil(t, err)
+}
+
+func TestServerInfo(t *testing.T) {
+	db := openMemory(t)
+	assert.Equal(t, "SQLITE", rds.DBTypeOf(db))
+
+	s, err := rds.ServerInfo(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, "SQLite", s.Product)
+	assert.NotEmpty(t, s.Version)
+
+	// 结果缓存在连接池上，连接池关闭后仍返回缓存
+	db.Close()
+	cached, err := rds.ServerInfo(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, s, cached)
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	db := openMemory(t)
+	c, err := rds.CapabilitiesOf(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, "SQLITE", c.DBType)
+	assert.NotEmpty(t, c.Version)
+	assert.True(t, c.LastInsertID)
+}
+
+func TestDialectOf(t *testing.T) {
+	db, err := sql.Open(rds.DriverName, "user:pwd@tcp(localhost:0)/:memory:?dbtype=sqlite3")
+	assert.Nil(t, err)
+	defer db.Close()
+	assert.Equal(t, "SQLITE", rds.DBTypeOf(db))
+
+	d, err := rds.DialectOf(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, "SQLITE", d.Name())
+
+	var now string
+	assert.Nil(t, db.QueryRow("SELECT "+d.CurrentTimestamp()+" WHERE "+d.BoolLiteral(true)).Scan(&now))
+	assert.NotEmpty(t, now)
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, rds.LockTimeout, Classify(sqlite3.Error{Code: sqlite3.ErrBusy}))
+	assert.Equal(t, rds.ConnectionLost, Classify(driver.ErrBadConn))
+
+	db := openMemory(t)
+	_, err := db.Exec("CREATE TABLE `t_err` (`f_id` INTEGER PRIMARY KEY)")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO `t_err` VALUES (?)", 1)
+	assert.Nil(t, err)
+
+	// 默认不包装错误，仍为 go-sqlite3 的原始类型
+	_, err = db.Exec("INSERT INTO `t_err` VALUES (?)", 1)
+	_, ok := err.(sqlite3.Error)
+	assert.True(t, ok)
+	assert.Equal(t, rds.UniqueViolation, rds.Classify(err))
+
+	tx, err := db.Begin()
+	assert.Nil(t, err)
+	_, err = tx.Exec("SELEC 1")
+	assert.Equal(t, rds.SyntaxError, rds.Classify(err))
+	assert.Nil(t, tx.Rollback())
+
+	conn, err := db.Conn(context.Background())
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Nil(t, conn.Raw(func(dc any) error {
+		_, ok := dc.(*sqlite3.SQLiteConn)
+		assert.True(t, ok)
+		return nil
+	}))
+}
+
+func TestWrapErrors(t *testing.T) {
+	rds.SetWrapErrors(true)
+	defer rds.SetWrapErrors(false)
+
+	db := openMemory(t)
+	_, err := db.Exec("CREATE TABLE `t_err` (`f_id` INTEGER PRIMARY KEY)")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO `t_err` VALUES (?)", 1)
+	assert.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO `t_err` VALUES (?)", 1)
+	assert.ErrorIs(t, err, rds.ErrUniqueViolation)
+	assert.NotErrorIs(t, err, rds.ErrDeadlock)
+	var se sqlite3.Error
+	assert.ErrorAs(t, err, &se)
+
+	conn, err := db.Conn(context.Background())
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Nil(t, conn.Raw(func(dc any) error {
+		_, ok := rds.UnwrapConn(dc).(*sqlite3.SQLiteConn)
+		assert.True(t, ok)
+		return nil
+	}))
+}
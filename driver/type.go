@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// Time 为扫描 DATETIME/TIMESTAMP 的时间类型，不带时区的文本按 time.Local 解析
+//
+// Deprecated: 使用 temporal 包的 Date、TimeOfDay、DateTime 和 Timestamp
 type Time struct {
 	time.Time
 }
@@ -15,6 +18,8 @@ func (T *Time) Scan(value interface{}) error {
 	case time.Time:
 		T.Time = v
 	case []byte:
+		return T.Scan(string(v))
+	case string:
 		for _, layout := range []string{
 			"2006-01-02T15:04:05Z07:00",
 			"2006-01-02T15:04:05.999999999Z07:00",
@@ -22,16 +27,17 @@ func (T *Time) Scan(value interface{}) error {
 			"2006-01-02",
 			"15:04:05",
 		} {
-			t, err := time.ParseInLocation(layout, string(v), time.Local)
+			t, err := time.ParseInLocation(layout, v, time.Local)
 			if err == nil {
 				T.Time = t
 				return nil
 			}
 		}
-		//T.t = time.Parse(time.RFC3339, string(v))
-		return fmt.Errorf("parse %s is unsupported", string(v))
+		return fmt.Errorf("parse %s is unsupported", v)
+	case nil:
+		T.Time = time.Time{}
 	default:
-		fmt.Println(v)
+		return fmt.Errorf("cannot scan %T into Time", value)
 	}
 	return nil
 }
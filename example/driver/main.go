@@ -8,8 +8,9 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	_ "github.com/kweaver-ai/proton-rds-sdk-go/driver"
 	"github.com/kweaver-ai/proton-rds-sdk-go/example/driver/kdb"
+	"github.com/kweaver-ai/proton-rds-sdk-go/temporal"
 )
 
 type TestDBInfo struct {
@@ -61,7 +62,7 @@ func testDM8() {
 		return
 	}
 
-	var t driver.Time
+	var t temporal.DateTime
 	var n string
 	//var t time.Time
 
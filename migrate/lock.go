@@ -0,0 +1,148 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"time"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// ErrLockTimeout 为在 LockTimeout 内未取得迁移锁
+var ErrLockTimeout = errors.New("migrate: timed out waiting for the migration lock")
+
+// lockPollInterval 为轮询迁移锁的间隔
+var lockPollInterval = 500 * time.Millisecond
+
+// locker 为迁移锁，锁在 conn 所在的会话上，防止多个实例同时执行迁移
+type locker interface {
+	lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error
+	unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+func newLocker(m *Migrator) locker {
+	switch m.dialect.Name() {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		return mysqlLocker{name: m.lockName()}
+	case "KDB9", "POSTGRES", "OPENGAUSS":
+		h := fnv.New64a()
+		h.Write([]byte(m.lockName()))
+		return pgLocker{key: int64(h.Sum64())}
+	}
+	return tableLocker{m: m}
+}
+
+// mysqlLocker 使用 GET_LOCK，锁名包含当前数据库名，同一实例上的不同数据库互不影响
+type mysqlLocker struct {
+	name string
+}
+
+func (l mysqlLocker) lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	var ok sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(COALESCE(DATABASE(), ''), '.', ?), ?)",
+		l.name, int64(timeout.Seconds())).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if ok.Int64 != 1 {
+		return ErrLockTimeout
+	}
+	return nil
+}
+
+func (l mysqlLocker) unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(CONCAT(COALESCE(DATABASE(), ''), '.', ?))", l.name)
+	return err
+}
+
+// pgLocker 使用会话级的 advisory lock，advisory lock 只在当前数据库内生效
+type pgLocker struct {
+	key int64
+}
+
+func (l pgLocker) lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	return poll(ctx, timeout, func() (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&ok)
+		return ok, err
+	})
+}
+
+func (l pgLocker) unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	return err
+}
+
+// tableLocker 用于 DM8、SQLite 等没有 advisory lock 的数据库，向锁表插入一行作为加锁，
+// 进程异常退出时锁不会自动释放，需手动删除锁表中的记录
+type tableLocker struct {
+	m *Migrator
+}
+
+func (l tableLocker) lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	d := l.m.dialect
+	table := d.QuoteIdentifier(l.m.lockTable())
+	exists, err := l.m.tableExists(ctx, conn, l.m.lockTable())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s INT NOT NULL PRIMARY KEY, %s VARCHAR(255) NOT NULL, %s VARCHAR(64) NOT NULL)",
+			table, d.QuoteIdentifier("id"), d.QuoteIdentifier("locked_by"), d.QuoteIdentifier("locked_at")))
+		// 其它实例同时创建时忽略错误，由下面的插入判断是否加锁成功
+		if err != nil {
+			if exists, _ = l.m.tableExists(ctx, conn, l.m.lockTable()); !exists {
+				return err
+			}
+		}
+	}
+
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", host, os.Getpid())
+	query := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (%s, %s, %s)", table,
+		d.QuoteIdentifier("id"), d.QuoteIdentifier("locked_by"), d.QuoteIdentifier("locked_at"),
+		d.Placeholder(1), d.Placeholder(2), d.Placeholder(3))
+	return poll(ctx, timeout, func() (bool, error) {
+		_, err := conn.ExecContext(ctx, query, 1, owner, time.Now().UTC().Format(time.RFC3339))
+		if rds.Classify(err) == rds.UniqueViolation {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+func (l tableLocker) unlock(ctx context.Context, conn *sql.Conn) error {
+	d := l.m.dialect
+	_, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = %s",
+		d.QuoteIdentifier(l.m.lockTable()), d.QuoteIdentifier("id"), d.Placeholder(1)), 1)
+	return err
+}
+
+// poll 反复调用 try 直到取得锁、出错、超时或 ctx 结束
+func poll(ctx context.Context, timeout time.Duration, try func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := try()
+		if err != nil || ok {
+			return err
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return ErrLockTimeout
+		}
+		if wait > lockPollInterval {
+			wait = lockPollInterval
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Package migrate 按版本执行数据库迁移，同一版本可为不同数据库提供不同的迁移文件
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	"github.com/kweaver-ai/proton-rds-sdk-go/schema"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+// Options 为迁移选项
+type Options struct {
+	// Table 为记录已执行版本的表名，默认为 schema_migrations
+	Table string
+	// LockTimeout 为等待迁移锁的最长时间，默认为 1 分钟
+	LockTimeout time.Duration
+	// DryRun 为 true 时 Up、Down 只返回将要执行的迁移，不执行语句，不加锁，也不创建版本表
+	DryRun bool
+}
+
+// Status 为一个版本的迁移状态
+type Status struct {
+	Version int64
+	Name    string
+	// Source 为迁移文件名，已执行但找不到迁移文件时为空
+	Source    string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator 执行 fsys 根目录下的迁移文件。迁移文件名为 <版本号>_<名称>[.<数据库类型>].sql，如：
+//
+//	0001_init.sql
+//	0003_add_idx.mysql.sql
+//	0003_add_idx.dm8.sql
+//	0003_add_idx.kdb9.sql
+//
+// 同一版本优先使用当前数据库类型的文件，MariaDB、GoldenDB、TiDB、OceanBase 可使用 .mysql.sql 文件，
+// 都没有时使用不带数据库类型的文件，名称中不能包含 .，未知的数据库类型返回错误。文件内通过 -- +migrate Up、-- +migrate Down 分隔升级和回滚语句
+type Migrator struct {
+	db   *sqlx.DB
+	fsys fs.FS
+	opts Options
+
+	dialect rds.Dialect
+}
+
+// New 创建 Migrator，opts 为空时使用默认选项
+func New(db *sqlx.DB, fsys fs.FS, opts *Options) *Migrator {
+	m := &Migrator{db: db, fsys: fsys}
+	if opts != nil {
+		m.opts = *opts
+	}
+	if m.opts.Table == "" {
+		m.opts.Table = "schema_migrations"
+	}
+	if m.opts.LockTimeout <= 0 {
+		m.opts.LockTimeout = time.Minute
+	}
+	return m
+}
+
+func (m *Migrator) lockTable() string {
+	return m.opts.Table + "_lock"
+}
+
+func (m *Migrator) lockName() string {
+	return m.opts.Table
+}
+
+// transactionalDDL 为 DDL 可以回滚的数据库，每个迁移在一个事务中执行；
+// MySQL 系和 DM8 的 DDL 隐式提交，迁移中途失败时已执行的语句不会回滚，需修复后手动处理
+func (m *Migrator) transactionalDDL() bool {
+	switch m.dialect.Name() {
+	case "KDB9", "POSTGRES", "OPENGAUSS", "SQLITE":
+		return true
+	}
+	return false
+}
+
+// Migrations 返回当前数据库类型适用的迁移，按版本号升序
+func (m *Migrator) Migrations(ctx context.Context) ([]Migration, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+	return load(m.fsys, m.dialect.Name())
+}
+
+// Status 返回所有迁移文件和已执行版本的状态，按版本号升序
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := m.Migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, 0, len(migrations))
+	for _, mg := range migrations {
+		s := Status{Version: mg.Version, Name: mg.Name, Source: mg.Source}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied, s.AppliedAt = true, a.AppliedAt
+			delete(applied, mg.Version)
+		}
+		status = append(status, s)
+	}
+	for _, a := range applied {
+		status = append(status, a)
+	}
+	sortStatus(status)
+	return status, nil
+}
+
+// Up 执行所有未执行的迁移，返回执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, -1)
+}
+
+// UpTo 执行版本号不大于 version 的未执行迁移，version 小于 0 时执行所有未执行的迁移
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]Migration, error) {
+	return m.run(ctx, func(migrations []Migration, applied map[int64]Status) ([]Migration, error) {
+		var plan []Migration
+		for _, mg := range migrations {
+			if _, ok := applied[mg.Version]; !ok && (version < 0 || mg.Version <= version) {
+				plan = append(plan, mg)
+			}
+		}
+		return plan, nil
+	}, true)
+}
+
+// Down 按版本号从大到小回滚最近执行的 steps 个迁移，返回回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	return m.run(ctx, func(migrations []Migration, applied map[int64]Status) ([]Migration, error) {
+		byVersion := make(map[int64]Migration, len(migrations))
+		for _, mg := range migrations {
+			byVersion[mg.Version] = mg
+		}
+		versions := make([]Status, 0, len(applied))
+		for _, a := range applied {
+			versions = append(versions, a)
+		}
+		sortStatus(versions)
+
+		var plan []Migration
+		for i := len(versions) - 1; i >= 0 && len(plan) < steps; i-- {
+			mg, ok := byVersion[versions[i].Version]
+			if !ok {
+				return nil, fmt.Errorf("migrate: migration file of applied version %d not found", versions[i].Version)
+			}
+			if len(mg.Down) == 0 {
+				return nil, fmt.Errorf("migrate: %s has no down statements", mg.Source)
+			}
+			plan = append(plan, mg)
+		}
+		return plan, nil
+	}, false)
+}
+
+// run 加锁后按 plan 返回的迁移执行升级或回滚，DryRun 时只返回 plan 的结果
+func (m *Migrator) run(ctx context.Context, plan func([]Migration, map[int64]Status) ([]Migration, error), up bool) ([]Migration, error) {
+	migrations, err := m.Migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if m.opts.DryRun {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		return plan(migrations, applied)
+	}
+
+	l := newLocker(m)
+	if err = l.lock(ctx, conn, m.opts.LockTimeout); err != nil {
+		return nil, err
+	}
+	defer l.unlock(context.WithoutCancel(ctx), conn)
+
+	if err = m.createTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	// 加锁后重新读取已执行的版本，其它实例可能已经执行了迁移
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := plan(migrations, applied)
+	if err != nil {
+		return nil, err
+	}
+	for i, mg := range todo {
+		if err = m.apply(ctx, conn, mg, up); err != nil {
+			return todo[:i], fmt.Errorf("migrate: %s: %w", mg.Source, err)
+		}
+	}
+	return todo, nil
+}
+
+// apply 执行一个迁移的语句并更新版本表
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mg Migration, up bool) error {
+	d := m.dialect
+	statements := mg.Up
+	var record string
+	var args []any
+	if up {
+		record = fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (%s, %s, %s)", d.QuoteIdentifier(m.opts.Table),
+			d.QuoteIdentifier("version"), d.QuoteIdentifier("name"), d.QuoteIdentifier("applied_at"),
+			d.Placeholder(1), d.Placeholder(2), d.Placeholder(3))
+		args = []any{mg.Version, mg.Name, time.Now().UTC().Format(time.RFC3339)}
+	} else {
+		statements = mg.Down
+		record = fmt.Sprintf("DELETE FROM %s WHERE %s = %s", d.QuoteIdentifier(m.opts.Table),
+			d.QuoteIdentifier("version"), d.Placeholder(1))
+		args = []any{mg.Version}
+	}
+
+	var exec interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	} = conn
+	var tx *sql.Tx
+	if m.transactionalDDL() {
+		var err error
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+	for _, stmt := range statements {
+		if _, err := exec.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := exec.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	if tx != nil {
+		return tx.Commit()
+	}
+	return nil
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+	if m.dialect != nil {
+		return nil
+	}
+	d, err := m.db.Dialect(ctx)
+	if err != nil {
+		return err
+	}
+	m.dialect = d
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn, table string) (bool, error) {
+	i, err := schema.New(conn, m.dialect.Name())
+	if err != nil {
+		return false, err
+	}
+	tables, err := i.Tables(ctx, "")
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tables {
+		if t.Name == table {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createTable 在版本表不存在时创建，applied_at 以 RFC 3339 格式的 UTC 时间保存，避免各驱动时间类型的差异
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	exists, err := m.tableExists(ctx, conn, m.opts.Table)
+	if err != nil || exists {
+		return err
+	}
+	d := m.dialect
+	_, err = conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s BIGINT NOT NULL PRIMARY KEY, %s VARCHAR(255) NOT NULL, %s VARCHAR(64) NOT NULL)",
+		d.QuoteIdentifier(m.opts.Table), d.QuoteIdentifier("version"), d.QuoteIdentifier("name"), d.QuoteIdentifier("applied_at")))
+	return err
+}
+
+// applied 返回已执行的版本，版本表不存在时返回空
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]Status, error) {
+	applied := make(map[int64]Status)
+	exists, err := m.tableExists(ctx, conn, m.opts.Table)
+	if err != nil || !exists {
+		return applied, err
+	}
+	d := m.dialect
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT %s, %s, %s FROM %s", d.QuoteIdentifier("version"),
+		d.QuoteIdentifier("name"), d.QuoteIdentifier("applied_at"), d.QuoteIdentifier(m.opts.Table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		s := Status{Applied: true}
+		var at string
+		if err = rows.Scan(&s.Version, &s.Name, &at); err != nil {
+			return nil, err
+		}
+		s.AppliedAt, _ = time.Parse(time.RFC3339, strings.TrimSpace(at))
+		applied[s.Version] = s
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,187 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	_ "github.com/kweaver-ai/proton-rds-sdk-go/driver/sqlite"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+func newSQLiteDB(t *testing.T) *sqlx.DB {
+	db, err := sqlx.NewDB(&sqlx.DBConfig{
+		User:     "user",
+		Password: "pwd",
+		Host:     "localhost",
+		Database: filepath.Join(t.TempDir(), "test.db"),
+		DBType:   "sqlite",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+var testFS = fstest.MapFS{
+	"0001_init.sql": {Data: []byte(`-- +migrate Up
+CREATE TABLE t_user (f_id INTEGER PRIMARY KEY, f_name VARCHAR(32));
+-- +migrate Down
+DROP TABLE t_user;
+`)},
+	"0002_add_idx.mysql.sql": {Data: []byte("ALTER TABLE t_user ADD INDEX idx_name (f_name);")},
+	"0002_add_idx.sql": {Data: []byte(`-- +migrate Up
+CREATE INDEX idx_name ON t_user (f_name);
+-- +migrate Down
+DROP INDEX idx_name;
+`)},
+	"0003_add_age.sql": {Data: []byte(`-- +migrate Up
+ALTER TABLE t_user ADD COLUMN f_age INTEGER;
+-- +migrate Down
+ALTER TABLE t_user DROP COLUMN f_age;
+`)},
+}
+
+func versions(migrations []Migration) []int64 {
+	var v []int64
+	for _, m := range migrations {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+
+	dry := New(db, testFS, &Options{DryRun: true})
+	plan, err := dry.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, versions(plan))
+	assert.Equal(t, "0002_add_idx.sql", plan[1].Source)
+	_, err = db.Exec("SELECT * FROM t_user")
+	assert.NotNil(t, err)
+
+	m := New(db, testFS, nil)
+	applied, err := m.UpTo(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, versions(applied))
+
+	status, err := m.Status(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(status))
+	assert.True(t, status[0].Applied)
+	assert.True(t, status[1].Applied)
+	assert.False(t, status[2].Applied)
+	assert.WithinDuration(t, time.Now(), status[0].AppliedAt, time.Minute)
+
+	applied, err = m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, versions(applied))
+	_, err = db.Exec("INSERT INTO t_user (f_id, f_name, f_age) VALUES (1, 'a', 10)")
+	assert.Nil(t, err)
+
+	applied, err = m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+
+	plan, err = dry.Down(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2}, versions(plan))
+
+	rolledBack, err := m.Down(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3, 2}, versions(rolledBack))
+	status, err = m.Status(ctx)
+	assert.Nil(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+	_, err = db.Exec("SELECT f_age FROM t_user")
+	assert.NotNil(t, err)
+}
+
+func TestMigratorFailure(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	fsys := fstest.MapFS{
+		"0001_init.sql":   testFS["0001_init.sql"],
+		"0002_broken.sql": {Data: []byte("CREATE TABLE t_dept (f_id INTEGER);\nCREATE TABLE t_user (f_id INTEGER);")},
+	}
+	m := New(db, fsys, nil)
+	applied, err := m.Up(ctx)
+	assert.NotNil(t, err)
+	assert.Equal(t, []int64{1}, versions(applied))
+
+	// SQLite 的 DDL 在事务中执行，失败的迁移整体回滚
+	_, err = db.Exec("SELECT * FROM t_dept")
+	assert.NotNil(t, err)
+	status, err := m.Status(ctx)
+	assert.Nil(t, err)
+	assert.False(t, status[1].Applied)
+}
+
+func TestMigratorLock(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	lockPollInterval = 10 * time.Millisecond
+
+	m := New(db, testFS, &Options{LockTimeout: 50 * time.Millisecond})
+	assert.Nil(t, m.init(ctx))
+	conn, err := db.Conn(ctx)
+	assert.Nil(t, err)
+	defer conn.Close()
+	l := newLocker(m)
+	assert.Nil(t, l.lock(ctx, conn, time.Second))
+
+	_, err = m.Up(ctx)
+	assert.True(t, errors.Is(err, ErrLockTimeout))
+
+	assert.Nil(t, l.unlock(ctx, conn))
+	applied, err := m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, versions(applied))
+}
+
+func TestAdvisoryLocker(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		dbType string
+		lock   string
+		unlock string
+		result any
+	}{
+		{"MYSQL", `SELECT GET_LOCK\(CONCAT\(COALESCE\(DATABASE\(\), ''\), '.', \?\), \?\)`, `SELECT RELEASE_LOCK`, int64(1)},
+		{"KDB9", `SELECT pg_try_advisory_lock\(\$1\)`, `SELECT pg_advisory_unlock\(\$1\)`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			db, mock, err := sqlx.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			d, _ := rds.DialectFor(tt.dbType)
+			db.SetDialect(d)
+			m := New(db, testFS, nil)
+			assert.Nil(t, m.init(ctx))
+			conn, err := db.Conn(ctx)
+			assert.Nil(t, err)
+			defer conn.Close()
+
+			mock.ExpectQuery(tt.lock).WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(tt.result))
+			mock.ExpectExec(tt.unlock).WillReturnResult(sqlmock.NewResult(0, 0))
+			l := newLocker(m)
+			assert.Nil(t, l.lock(ctx, conn, time.Second))
+			assert.Nil(t, l.unlock(ctx, conn))
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// Migration 为一个版本的迁移，Up、Down 为拆分后的语句
+type Migration struct {
+	Version int64
+	Name    string
+	// Source 为选中的迁移文件名
+	Source string
+	Up     []string
+	Down   []string
+}
+
+// 迁移文件中的标记，与 sql-migrate 兼容，没有标记的文件全部作为 Up
+const (
+	markerUp             = "-- +migrate Up"
+	markerDown           = "-- +migrate Down"
+	markerStatementBegin = "-- +migrate StatementBegin"
+	markerStatementEnd   = "-- +migrate StatementEnd"
+)
+
+// fileNamePattern 匹配 0003_add_idx.sql 和 0003_add_idx.mysql.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([^.]+)(?:\.([A-Za-z0-9]+))?\.sql$`)
+
+// mysqlFamily 为没有专用迁移文件时可使用 .mysql.sql 文件的数据库类型
+var mysqlFamily = map[string]bool{"MARIADB": true, "GOLDENDB": true, "TIDB": true, "OCEANBASE": true}
+
+// backslashEscapes 判断字符串字面量中的反斜杠是否为转义符，只有 MySQL 系如此，
+// DM8、Kingbase、PostgreSQL 和 SQLite 使用标准字符串，'a\' 是完整的字面量
+func backslashEscapes(dbType string) bool {
+	return dbType == "MYSQL" || mysqlFamily[dbType]
+}
+
+// knownVariant 判断迁移文件名中的变体是否为已注册的数据库类型或方言
+func knownVariant(variant string) bool {
+	if _, ok := rds.DialectFor(variant); ok {
+		return true
+	}
+	_, ok := rds.LookupBackend(variant)
+	return ok
+}
+
+// variantRank 返回迁移文件对数据库类型的匹配程度，专用文件优先，MySQL 系退回到 .mysql.sql，
+// 再退回到通用文件，-1 表示不适用
+func variantRank(variant, dbType string) int {
+	switch {
+	case variant == "":
+		return 0
+	case variant == dbType:
+		return 2
+	case variant == "MYSQL" && mysqlFamily[dbType]:
+		return 1
+	}
+	return -1
+}
+
+// load 读取 fsys 根目录下的迁移文件，每个版本按 dbType 选择一个文件，按版本号升序返回
+func load(fsys fs.FS, dbType string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	type candidate struct {
+		name, file string
+		rank       int
+	}
+	chosen := make(map[int64]candidate)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version in %s: %w", e.Name(), err)
+		}
+		variant := ""
+		if m[3] != "" {
+			variant = rds.NormalizeDBType(m[3])
+			if !knownVariant(variant) {
+				return nil, fmt.Errorf("migrate: unknown database type %q in %s", m[3], e.Name())
+			}
+		}
+		rank := variantRank(variant, dbType)
+		if rank < 0 {
+			continue
+		}
+		if c, ok := chosen[version]; ok {
+			if c.rank == rank {
+				return nil, fmt.Errorf("migrate: duplicate migration %d: %s and %s", version, c.file, e.Name())
+			}
+			if c.rank > rank {
+				continue
+			}
+		}
+		chosen[version] = candidate{name: m[2], file: e.Name(), rank: rank}
+	}
+
+	migrations := make([]Migration, 0, len(chosen))
+	for version, c := range chosen {
+		b, err := fs.ReadFile(fsys, c.file)
+		if err != nil {
+			return nil, err
+		}
+		up, down, err := parse(string(b), backslashEscapes(dbType))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", c.file, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: c.name, Source: c.file, Up: up, Down: down})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parse 按 Up、Down 标记拆分迁移文件，StatementBegin 和 StatementEnd 之间的内容作为一条语句，
+// 用于包含分号的存储过程和触发器，backslash 表示单引号字符串中的反斜杠为转义符
+func parse(content string, backslash bool) (up, down []string, err error) {
+	section := &up
+	var buf strings.Builder
+	inBlock := false
+	flush := func() {
+		*section = append(*section, splitStatements(buf.String(), backslash)...)
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case markerUp:
+			flush()
+			section = &up
+			continue
+		case markerDown:
+			flush()
+			section = &down
+			continue
+		case markerStatementBegin:
+			flush()
+			inBlock = true
+			continue
+		case markerStatementEnd:
+			if !inBlock {
+				return nil, nil, fmt.Errorf("unexpected %q", markerStatementEnd)
+			}
+			if s := strings.TrimSpace(buf.String()); s != "" {
+				*section = append(*section, s)
+			}
+			buf.Reset()
+			inBlock = false
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if inBlock {
+		return nil, nil, fmt.Errorf("missing %q", markerStatementEnd)
+	}
+	flush()
+	return up, down, nil
+}
+
+// splitStatements 按分号拆分语句，忽略引号、反引号和注释中的分号，丢弃只有注释的语句，
+// backslash 为 false 时单引号字符串中的反斜杠是普通字符
+func splitStatements(s string, backslash bool) []string {
+	var statements []string
+	start := 0
+	hasCode := false
+	add := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(s[start:end]))
+		}
+		start = end + 1
+		hasCode = false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			hasCode = true
+			for i++; i < len(s) && s[i] != c; i++ {
+				if backslash && s[i] == '\\' && c == '\'' {
+					i++
+				}
+			}
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += end + 3
+			}
+		case c == ';':
+			add(i)
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			hasCode = true
+		}
+	}
+	add(len(s))
+	return statements
+}
+
+func sortStatus(status []Status) {
+	sort.Slice(status, func(i, j int) bool { return status[i].Version < status[j].Version })
+}
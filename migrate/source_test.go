@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		backslash bool
+		want      []string
+	}{
+		{"single", "CREATE TABLE t (a INT)", false, []string{"CREATE TABLE t (a INT)"}},
+		{"multiple", "CREATE TABLE t (a INT);\nCREATE INDEX i ON t (a);\n", false, []string{"CREATE TABLE t (a INT)", "CREATE INDEX i ON t (a)"}},
+		{"quoted", "INSERT INTO t VALUES ('a;b', \"c;d\", `e;f`);", false, []string{"INSERT INTO t VALUES ('a;b', \"c;d\", `e;f`)"}},
+		{"escaped", `INSERT INTO t VALUES ('a\';b');`, true, []string{`INSERT INTO t VALUES ('a\';b')`}},
+		{"standard", `INSERT INTO t VALUES ('a\'); SELECT 'b''c;';`, false, []string{`INSERT INTO t VALUES ('a\')`, `SELECT 'b''c;'`}},
+		{"comments", "-- a; b\nSELECT 1; /* c; d */\n-- trailing", false, []string{"-- a; b\nSELECT 1"}},
+		{"empty", "  ;\n;", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.sql, tt.backslash))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	up, down, err := parse(`-- +migrate Up
+CREATE TABLE t (a INT);
+-- +migrate StatementBegin
+CREATE PROCEDURE p AS BEGIN SELECT 1; END;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP PROCEDURE p;
+DROP TABLE t;
+`, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CREATE TABLE t (a INT)", "CREATE PROCEDURE p AS BEGIN SELECT 1; END;"}, up)
+	assert.Equal(t, []string{"DROP PROCEDURE p", "DROP TABLE t"}, down)
+
+	up, down, err = parse("CREATE TABLE t (a INT);", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CREATE TABLE t (a INT)"}, up)
+	assert.Nil(t, down)
+
+	_, _, err = parse("-- +migrate StatementBegin\nSELECT 1;", false)
+	assert.NotNil(t, err)
+	_, _, err = parse("-- +migrate StatementEnd", false)
+	assert.NotNil(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_init.sql":          {Data: []byte("CREATE TABLE t (a INT);")},
+		"0002_add_idx.sql":       {Data: []byte("CREATE INDEX i ON t (a);")},
+		"0002_add_idx.mysql.sql": {Data: []byte("ALTER TABLE t ADD INDEX i (a);")},
+		"0002_add_idx.dm8.sql":   {Data: []byte("CREATE INDEX i ON t (a) STORAGE (ON MAIN);")},
+		"0003_only_kb.kdb9.sql":  {Data: []byte("SELECT 1;")},
+		"README.md":              {Data: []byte("not a migration")},
+	}
+	tests := []struct {
+		dbType  string
+		sources []string
+	}{
+		{"MYSQL", []string{"0001_init.sql", "0002_add_idx.mysql.sql"}},
+		{"TIDB", []string{"0001_init.sql", "0002_add_idx.mysql.sql"}},
+		{"DM8", []string{"0001_init.sql", "0002_add_idx.dm8.sql"}},
+		{"KDB9", []string{"0001_init.sql", "0002_add_idx.sql", "0003_only_kb.kdb9.sql"}},
+		{"SQLITE", []string{"0001_init.sql", "0002_add_idx.sql"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			migrations, err := load(fsys, tt.dbType)
+			assert.Nil(t, err)
+			var sources []string
+			for _, m := range migrations {
+				sources = append(sources, m.Source)
+			}
+			assert.Equal(t, tt.sources, sources)
+		})
+	}
+
+	fsys["0004_add.idx.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	_, err := load(fsys, "MYSQL")
+	assert.ErrorContains(t, err, "0004_add.idx.sql")
+	delete(fsys, "0004_add.idx.sql")
+
+	fsys["0001_other.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	_, err = load(fsys, "MYSQL")
+	assert.NotNil(t, err)
+}
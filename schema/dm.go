@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// dmDecimalTypes 为 DM 中可指定精度和小数位数的类型
+var dmDecimalTypes = map[string]bool{"number": true, "numeric": true, "decimal": true, "dec": true}
+
+// dmCatalog 查询 DM8 的 ALL_TAB_COLUMNS、ALL_INDEXES、ALL_CONSTRAINTS 等数据字典视图，schema 为模式名
+type dmCatalog struct {
+	q Queryer
+}
+
+func (c dmCatalog) currentSchema(ctx context.Context) (string, error) {
+	return queryString(ctx, c.q, "SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL")
+}
+
+func (c dmCatalog) tables(ctx context.Context, schema string) ([]Table, error) {
+	const query = "SELECT TABLE_NAME, TABLE_TYPE, COMMENTS FROM ALL_TAB_COMMENTS WHERE OWNER = ? ORDER BY TABLE_NAME"
+	var tables []Table
+	err := queryRows(ctx, c.q, query, []interface{}{schema}, func(rows *sql.Rows) error {
+		var t Table
+		var typ, comment sql.NullString
+		if err := rows.Scan(&t.Name, &typ, &comment); err != nil {
+			return err
+		}
+		t.Schema = schema
+		t.View = typ.String == "VIEW"
+		t.Comment = comment.String
+		tables = append(tables, t)
+		return nil
+	})
+	return tables, err
+}
+
+func (c dmCatalog) columns(ctx context.Context, schema, table string) ([]Column, error) {
+	const query = "SELECT c.COLUMN_NAME, c.DATA_TYPE, c.NULLABLE, c.DATA_DEFAULT, c.DATA_LENGTH, " +
+		"c.DATA_PRECISION, c.DATA_SCALE, c.COLUMN_ID, m.COMMENTS FROM ALL_TAB_COLUMNS c " +
+		"LEFT JOIN ALL_COL_COMMENTS m ON m.OWNER = c.OWNER AND m.TABLE_NAME = c.TABLE_NAME AND m.COLUMN_NAME = c.COLUMN_NAME " +
+		"WHERE c.OWNER = ? AND c.TABLE_NAME = ? ORDER BY c.COLUMN_ID"
+	var columns []Column
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var col Column
+		var nullable string
+		var def, comment sql.NullString
+		var length, precision, scale sql.NullInt64
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &def, &length, &precision, &scale,
+			&col.Position, &comment); err != nil {
+			return err
+		}
+		col.DataType = strings.ToLower(col.Type)
+		col.Nullable = nullable == "Y"
+		if def.Valid {
+			col.Default = &def.String
+		}
+		// DATA_LENGTH 对数值类型为存储字节数，只对字符和二进制类型有意义
+		switch {
+		case strings.Contains(col.DataType, "char") || strings.Contains(col.DataType, "binary"):
+			col.Length = length.Int64
+			col.Type += "(" + formatInt(length.Int64) + ")"
+		case precision.Valid && dmDecimalTypes[col.DataType]:
+			col.Precision, col.Scale = precision.Int64, scale.Int64
+			col.Type += "(" + formatInt(precision.Int64) + "," + formatInt(scale.Int64) + ")"
+		case precision.Valid:
+			col.Precision = precision.Int64
+		}
+		col.Comment = comment.String
+		columns = append(columns, col)
+		return nil
+	})
+	if err != nil || len(columns) == 0 {
+		return columns, err
+	}
+
+	// 自增列记录在 SYSCOLUMNS 的 INFO2 中
+	const identity = "SELECT c.NAME FROM SYSCOLUMNS c JOIN SYSOBJECTS t ON t.ID = c.ID " +
+		"JOIN SYSOBJECTS s ON s.ID = t.SCHID WHERE s.NAME = ? AND t.NAME = ? AND c.INFO2 & 1 = 1"
+	err = queryRows(ctx, c.q, identity, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		for i := range columns {
+			if columns[i].Name == name {
+				columns[i].AutoIncrement = true
+			}
+		}
+		return nil
+	})
+	return columns, err
+}
+
+func (c dmCatalog) primaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error) {
+	const query = "SELECT c.CONSTRAINT_NAME, cc.COLUMN_NAME FROM ALL_CONSTRAINTS c " +
+		"JOIN ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME " +
+		"WHERE c.OWNER = ? AND c.TABLE_NAME = ? AND c.CONSTRAINT_TYPE = 'P' ORDER BY cc.POSITION"
+	var pk *PrimaryKey
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name, column string
+		if err := rows.Scan(&name, &column); err != nil {
+			return err
+		}
+		if pk == nil {
+			pk = &PrimaryKey{Name: name}
+		}
+		pk.Columns = append(pk.Columns, column)
+		return nil
+	})
+	return pk, err
+}
+
+func (c dmCatalog) indexes(ctx context.Context, schema, table string) ([]Index, error) {
+	const query = "SELECT i.INDEX_NAME, i.UNIQUENESS, p.CONSTRAINT_NAME, c.COLUMN_NAME FROM ALL_INDEXES i " +
+		"JOIN ALL_IND_COLUMNS c ON c.INDEX_OWNER = i.OWNER AND c.INDEX_NAME = i.INDEX_NAME " +
+		"LEFT JOIN ALL_CONSTRAINTS p ON p.OWNER = i.TABLE_OWNER AND p.INDEX_NAME = i.INDEX_NAME AND p.CONSTRAINT_TYPE = 'P' " +
+		"WHERE i.TABLE_OWNER = ? AND i.TABLE_NAME = ? ORDER BY i.INDEX_NAME, c.COLUMN_POSITION"
+	var named namedColumns
+	unique := make(map[string]bool)
+	primary := make(map[string]bool)
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name, uniqueness, column string
+		var constraint sql.NullString
+		if err := rows.Scan(&name, &uniqueness, &constraint, &column); err != nil {
+			return err
+		}
+		unique[name] = uniqueness == "UNIQUE"
+		primary[name] = constraint.Valid
+		named.add(name, column)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]Index, len(named.names))
+	for i, name := range named.names {
+		indexes[i] = Index{Name: name, Columns: named.columns[name], Unique: unique[name], Primary: primary[name]}
+	}
+	return indexes, nil
+}
+
+func (c dmCatalog) foreignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	const query = "SELECT c.CONSTRAINT_NAME, cc.COLUMN_NAME, r.OWNER, r.TABLE_NAME, rc.COLUMN_NAME, c.DELETE_RULE " +
+		"FROM ALL_CONSTRAINTS c " +
+		"JOIN ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME " +
+		"JOIN ALL_CONSTRAINTS r ON r.OWNER = c.R_OWNER AND r.CONSTRAINT_NAME = c.R_CONSTRAINT_NAME " +
+		"JOIN ALL_CONS_COLUMNS rc ON rc.OWNER = r.OWNER AND rc.CONSTRAINT_NAME = r.CONSTRAINT_NAME AND rc.POSITION = cc.POSITION " +
+		"WHERE c.OWNER = ? AND c.TABLE_NAME = ? AND c.CONSTRAINT_TYPE = 'R' ORDER BY c.CONSTRAINT_NAME, cc.POSITION"
+	var fks []ForeignKey
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name, column, refSchema, refTable, refColumn string
+		var onDelete sql.NullString
+		if err := rows.Scan(&name, &column, &refSchema, &refTable, &refColumn, &onDelete); err != nil {
+			return err
+		}
+		if len(fks) == 0 || fks[len(fks)-1].Name != name {
+			// DM 不支持 ON UPDATE 动作
+			fk := ForeignKey{Name: name, RefSchema: refSchema, RefTable: refTable, OnUpdate: "NO ACTION", OnDelete: "NO ACTION"}
+			if onDelete.String != "" {
+				fk.OnDelete = ruleName(onDelete.String)
+			}
+			fks = append(fks, fk)
+		}
+		fk := &fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+		return nil
+	})
+	return fks, err
+}
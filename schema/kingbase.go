@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pgCatalog 查询 Kingbase 的 sys_catalog 或 PostgreSQL、openGauss 的 pg_catalog，
+// Kingbase 各 database_mode 下的系统表均为 sys_ 前缀，schema 为模式名
+type pgCatalog struct {
+	q      Queryer
+	prefix string
+}
+
+// sql 将语句中的 {p} 替换为系统表前缀
+func (c pgCatalog) sql(query string) string {
+	return strings.ReplaceAll(query, "{p}", c.prefix)
+}
+
+func (c pgCatalog) currentSchema(ctx context.Context) (string, error) {
+	return queryString(ctx, c.q, "SELECT current_schema()")
+}
+
+func (c pgCatalog) tables(ctx context.Context, schema string) ([]Table, error) {
+	query := c.sql("SELECT c.relname, c.relkind, d.description FROM {p}_class c " +
+		"JOIN {p}_namespace n ON n.oid = c.relnamespace " +
+		"LEFT JOIN {p}_description d ON d.objoid = c.oid AND d.objsubid = 0 " +
+		"WHERE n.nspname = ? AND c.relkind IN ('r', 'p', 'v', 'm') ORDER BY c.relname")
+	var tables []Table
+	err := queryRows(ctx, c.q, query, []interface{}{schema}, func(rows *sql.Rows) error {
+		var t Table
+		var kind string
+		var comment sql.NullString
+		if err := rows.Scan(&t.Name, &kind, &comment); err != nil {
+			return err
+		}
+		t.Schema = schema
+		t.View = kind == "v" || kind == "m"
+		t.Comment = comment.String
+		tables = append(tables, t)
+		return nil
+	})
+	return tables, err
+}
+
+func (c pgCatalog) columns(ctx context.Context, schema, table string) ([]Column, error) {
+	query := c.sql("SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, " +
+		"{p}_get_expr(ad.adbin, ad.adrelid), a.attnum, d.description FROM {p}_attribute a " +
+		"JOIN {p}_class c ON c.oid = a.attrelid JOIN {p}_namespace n ON n.oid = c.relnamespace " +
+		"LEFT JOIN {p}_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum " +
+		"LEFT JOIN {p}_description d ON d.objoid = a.attrelid AND d.objsubid = a.attnum " +
+		"WHERE n.nspname = ? AND c.relname = ? AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum")
+	var columns []Column
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var col Column
+		var notNull bool
+		var def, comment sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &def, &col.Position, &comment); err != nil {
+			return err
+		}
+		col.DataType, col.Length, col.Precision, col.Scale = parseType(col.Type)
+		col.Nullable = !notNull
+		if def.Valid {
+			col.Default = &def.String
+			// serial 列的默认值为 nextval('seq'::regclass)
+			col.AutoIncrement = strings.HasPrefix(def.String, "nextval(")
+		}
+		col.Comment = comment.String
+		columns = append(columns, col)
+		return nil
+	})
+	return columns, err
+}
+
+// attnames 返回表的列序号到列名的映射
+func (c pgCatalog) attnames(ctx context.Context, schema, table string) (map[int]string, error) {
+	query := c.sql("SELECT a.attnum, a.attname FROM {p}_attribute a " +
+		"JOIN {p}_class c ON c.oid = a.attrelid JOIN {p}_namespace n ON n.oid = c.relnamespace " +
+		"WHERE n.nspname = ? AND c.relname = ? AND a.attnum > 0")
+	names := make(map[int]string)
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var num int
+		var name string
+		if err := rows.Scan(&num, &name); err != nil {
+			return err
+		}
+		names[num] = name
+		return nil
+	})
+	return names, err
+}
+
+var attnumPattern = regexp.MustCompile(`\d+`)
+
+// attnumColumns 将 int2vector 或 int2[] 的文本形式（"1 3" 或 "{1,3}"）转换为列名，表达式列（0）被忽略
+func attnumColumns(keys string, names map[int]string) []string {
+	var columns []string
+	for _, s := range attnumPattern.FindAllString(keys, -1) {
+		n, _ := strconv.Atoi(s)
+		if name, ok := names[n]; ok {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+func (c pgCatalog) primaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error) {
+	query := c.sql("SELECT con.conname, CAST(con.conkey AS TEXT) FROM {p}_constraint con " +
+		"JOIN {p}_class c ON c.oid = con.conrelid JOIN {p}_namespace n ON n.oid = c.relnamespace " +
+		"WHERE n.nspname = ? AND c.relname = ? AND con.contype = 'p'")
+	var pk *PrimaryKey
+	var keys string
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		pk = &PrimaryKey{}
+		return rows.Scan(&pk.Name, &keys)
+	})
+	if err != nil || pk == nil {
+		return nil, err
+	}
+	names, err := c.attnames(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	pk.Columns = attnumColumns(keys, names)
+	return pk, nil
+}
+
+func (c pgCatalog) indexes(ctx context.Context, schema, table string) ([]Index, error) {
+	query := c.sql("SELECT i.relname, x.indisunique, x.indisprimary, CAST(x.indkey AS TEXT) FROM {p}_index x " +
+		"JOIN {p}_class t ON t.oid = x.indrelid JOIN {p}_namespace n ON n.oid = t.relnamespace " +
+		"JOIN {p}_class i ON i.oid = x.indexrelid WHERE n.nspname = ? AND t.relname = ? ORDER BY i.relname")
+	var indexes []Index
+	var keys []string
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var idx Index
+		var key string
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Primary, &key); err != nil {
+			return err
+		}
+		indexes = append(indexes, idx)
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil || len(indexes) == 0 {
+		return indexes, err
+	}
+	names, err := c.attnames(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	for i := range indexes {
+		indexes[i].Columns = attnumColumns(keys[i], names)
+	}
+	return indexes, nil
+}
+
+// pgActions 为 confupdtype、confdeltype 对应的外键动作
+var pgActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+func (c pgCatalog) foreignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	query := c.sql("SELECT con.conname, CAST(con.conkey AS TEXT), rn.nspname, rc.relname, CAST(con.confkey AS TEXT), " +
+		"con.confupdtype, con.confdeltype FROM {p}_constraint con " +
+		"JOIN {p}_class c ON c.oid = con.conrelid JOIN {p}_namespace n ON n.oid = c.relnamespace " +
+		"JOIN {p}_class rc ON rc.oid = con.confrelid JOIN {p}_namespace rn ON rn.oid = rc.relnamespace " +
+		"WHERE n.nspname = ? AND c.relname = ? AND con.contype = 'f' ORDER BY con.conname")
+	var fks []ForeignKey
+	var keys, refKeys []string
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var fk ForeignKey
+		var key, refKey, onUpdate, onDelete string
+		if err := rows.Scan(&fk.Name, &key, &fk.RefSchema, &fk.RefTable, &refKey, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		fk.OnUpdate, fk.OnDelete = pgActions[onUpdate], pgActions[onDelete]
+		fks = append(fks, fk)
+		keys = append(keys, key)
+		refKeys = append(refKeys, refKey)
+		return nil
+	})
+	if err != nil || len(fks) == 0 {
+		return fks, err
+	}
+	names, err := c.attnames(ctx, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fks {
+		refNames, err := c.attnames(ctx, fks[i].RefSchema, fks[i].RefTable)
+		if err != nil {
+			return nil, err
+		}
+		fks[i].Columns = attnumColumns(keys[i], names)
+		fks[i].RefColumns = attnumColumns(refKeys[i], refNames)
+	}
+	return fks, nil
+}
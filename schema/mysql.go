@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// mysqlCatalog 查询 MySQL、MariaDB、GoldenDB、TiDB、OceanBase 的 information_schema
+type mysqlCatalog struct {
+	q Queryer
+}
+
+func (c mysqlCatalog) currentSchema(ctx context.Context) (string, error) {
+	return queryString(ctx, c.q, "SELECT DATABASE()")
+}
+
+func (c mysqlCatalog) tables(ctx context.Context, schema string) ([]Table, error) {
+	const query = "SELECT TABLE_NAME, TABLE_TYPE, TABLE_COMMENT FROM information_schema.TABLES " +
+		"WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME"
+	var tables []Table
+	err := queryRows(ctx, c.q, query, []interface{}{schema}, func(rows *sql.Rows) error {
+		var t Table
+		var typ string
+		var comment sql.NullString
+		if err := rows.Scan(&t.Name, &typ, &comment); err != nil {
+			return err
+		}
+		t.Schema = schema
+		t.View = strings.Contains(typ, "VIEW")
+		t.Comment = comment.String
+		tables = append(tables, t)
+		return nil
+	})
+	return tables, err
+}
+
+func (c mysqlCatalog) columns(ctx context.Context, schema, table string) ([]Column, error) {
+	const query = "SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, " +
+		"CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, EXTRA, ORDINAL_POSITION, COLUMN_COMMENT " +
+		"FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION"
+	var columns []Column
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var col Column
+		var nullable, extra string
+		var def, comment sql.NullString
+		var length, precision, scale sql.NullInt64
+		if err := rows.Scan(&col.Name, &col.DataType, &col.Type, &nullable, &def,
+			&length, &precision, &scale, &extra, &col.Position, &comment); err != nil {
+			return err
+		}
+		col.DataType = strings.ToLower(col.DataType)
+		col.Nullable = nullable == "YES"
+		if def.Valid {
+			col.Default = &def.String
+		}
+		col.Length, col.Precision, col.Scale = length.Int64, precision.Int64, scale.Int64
+		col.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		col.Comment = comment.String
+		columns = append(columns, col)
+		return nil
+	})
+	return columns, err
+}
+
+func (c mysqlCatalog) primaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error) {
+	const query = "SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY' ORDER BY ORDINAL_POSITION"
+	var pk *PrimaryKey
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		if pk == nil {
+			pk = &PrimaryKey{Name: "PRIMARY"}
+		}
+		pk.Columns = append(pk.Columns, column)
+		return nil
+	})
+	return pk, err
+}
+
+func (c mysqlCatalog) indexes(ctx context.Context, schema, table string) ([]Index, error) {
+	const query = "SELECT INDEX_NAME, NON_UNIQUE, COLUMN_NAME FROM information_schema.STATISTICS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+	var named namedColumns
+	unique := make(map[string]bool)
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name string
+		var nonUnique int
+		var column sql.NullString
+		if err := rows.Scan(&name, &nonUnique, &column); err != nil {
+			return err
+		}
+		unique[name] = nonUnique == 0
+		// MySQL 8.0 的函数索引 COLUMN_NAME 为 NULL
+		if column.Valid {
+			named.add(name, column.String)
+		} else {
+			named.add(name, "")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]Index, len(named.names))
+	for i, name := range named.names {
+		indexes[i] = Index{Name: name, Columns: nonEmpty(named.columns[name]), Unique: unique[name], Primary: name == "PRIMARY"}
+	}
+	return indexes, nil
+}
+
+func (c mysqlCatalog) foreignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	const query = "SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, " +
+		"k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE FROM information_schema.KEY_COLUMN_USAGE k " +
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS r " +
+		"ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME " +
+		"WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL " +
+		"ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION"
+	var fks []ForeignKey
+	err := queryRows(ctx, c.q, query, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var name, column, refSchema, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refSchema, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		if len(fks) == 0 || fks[len(fks)-1].Name != name {
+			fks = append(fks, ForeignKey{Name: name, RefSchema: refSchema, RefTable: refTable,
+				OnUpdate: ruleName(onUpdate), OnDelete: ruleName(onDelete)})
+		}
+		fk := &fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+		return nil
+	})
+	return fks, err
+}
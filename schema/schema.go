@@ -0,0 +1,291 @@
+// Package schema 查询 MySQL 系、DM8、Kingbase、PostgreSQL 和 SQLite 的表结构，返回统一的模型
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+// Queryer 为执行查询的对象，*sql.DB、*sql.Tx、*sql.Conn、*sqlx.DB 均满足
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Table 为表或视图，Tables 只返回基本信息，Describe 同时返回列、主键、索引和外键
+type Table struct {
+	Schema  string
+	Name    string
+	View    bool
+	Comment string
+
+	Columns     []Column
+	PrimaryKey  *PrimaryKey
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// Column 为表的列
+type Column struct {
+	Name string
+	// Type 为数据库中声明的完整类型，如 varchar(32)、NUMBER(10,2)、character varying(32)
+	Type string
+	// DataType 为不含长度和精度的小写类型名，如 varchar、number、character varying
+	DataType string
+	Nullable bool
+	// Default 为默认值表达式，没有默认值时为空
+	Default *string
+	// Length 为字符和二进制类型的长度，Precision、Scale 为数值类型的精度和小数位数，未知时为 0
+	Length        int64
+	Precision     int64
+	Scale         int64
+	AutoIncrement bool
+	// Position 为列的序号，从 1 开始
+	Position int
+	Comment  string
+}
+
+// PrimaryKey 为表的主键
+type PrimaryKey struct {
+	Name    string
+	Columns []string
+}
+
+// Index 为表的索引，表达式索引中的表达式列被忽略
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// ForeignKey 为表的外键，OnUpdate、OnDelete 为 NO ACTION、RESTRICT、CASCADE、SET NULL、SET DEFAULT
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+// catalog 为各数据库查询系统表的实现，schema 参数已解析为非空
+type catalog interface {
+	currentSchema(ctx context.Context) (string, error)
+	tables(ctx context.Context, schema string) ([]Table, error)
+	columns(ctx context.Context, schema, table string) ([]Column, error)
+	primaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error)
+	indexes(ctx context.Context, schema, table string) ([]Index, error)
+	foreignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error)
+}
+
+var supported = []string{"DM8", "GOLDENDB", "KDB9", "MARIADB", "MYSQL", "OCEANBASE", "OPENGAUSS", "POSTGRES", "SQLITE", "TIDB"}
+
+// Inspector 查询表结构，schema 参数为空时使用当前连接的默认 schema（MySQL 为当前数据库，DM 为当前模式）
+type Inspector struct {
+	catalog catalog
+}
+
+// New 按数据库类型创建 Inspector，不支持的数据库类型返回 *driver.UnsupportedDBTypeError
+func New(q Queryer, dbType string) (*Inspector, error) {
+	dbType = rds.NormalizeDBType(dbType)
+	var c catalog
+	switch dbType {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		c = mysqlCatalog{q: q}
+	case "DM8":
+		c = dmCatalog{q: q}
+	case "KDB9":
+		c = pgCatalog{q: q, prefix: "sys"}
+	case "POSTGRES", "OPENGAUSS":
+		c = pgCatalog{q: q, prefix: "pg"}
+	case "SQLITE":
+		c = sqliteCatalog{q: q}
+	default:
+		return nil, &rds.UnsupportedDBTypeError{DBType: dbType, Supported: supported}
+	}
+	return &Inspector{catalog: c}, nil
+}
+
+// NewFromDB 按连接池的方言创建 Inspector，查询通过 db 的读库执行
+func NewFromDB(ctx context.Context, db *sqlx.DB) (*Inspector, error) {
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(db, d.Name())
+}
+
+func (i *Inspector) schema(ctx context.Context, schema string) (string, error) {
+	if schema != "" {
+		return schema, nil
+	}
+	return i.catalog.currentSchema(ctx)
+}
+
+// Tables 返回 schema 下的表和视图，按名字排序
+func (i *Inspector) Tables(ctx context.Context, schema string) ([]Table, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.catalog.tables(ctx, schema)
+}
+
+// Columns 返回表的列，按列的序号排序
+func (i *Inspector) Columns(ctx context.Context, schema, table string) ([]Column, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.catalog.columns(ctx, schema, table)
+}
+
+// PrimaryKey 返回表的主键，没有主键时返回空
+func (i *Inspector) PrimaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.catalog.primaryKey(ctx, schema, table)
+}
+
+// Indexes 返回表的索引，包括主键对应的索引，按名字排序
+func (i *Inspector) Indexes(ctx context.Context, schema, table string) ([]Index, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.catalog.indexes(ctx, schema, table)
+}
+
+// ForeignKeys 返回表的外键，按名字排序
+func (i *Inspector) ForeignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	return i.catalog.foreignKeys(ctx, schema, table)
+}
+
+// Describe 返回表的基本信息、列、主键、索引和外键，表不存在时返回空
+func (i *Inspector) Describe(ctx context.Context, schema, table string) (*Table, error) {
+	schema, err := i.schema(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := i.catalog.tables(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	var t *Table
+	for k := range tables {
+		if tables[k].Name == table {
+			t = &tables[k]
+			break
+		}
+	}
+	if t == nil {
+		return nil, nil
+	}
+	if t.Columns, err = i.catalog.columns(ctx, schema, table); err != nil {
+		return nil, err
+	}
+	if t.PrimaryKey, err = i.catalog.primaryKey(ctx, schema, table); err != nil {
+		return nil, err
+	}
+	if t.Indexes, err = i.catalog.indexes(ctx, schema, table); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = i.catalog.foreignKeys(ctx, schema, table); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// queryRows 执行查询并对每一行调用 scan
+func queryRows(ctx context.Context, q Queryer, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func queryString(ctx context.Context, q Queryer, query string) (string, error) {
+	var s sql.NullString
+	err := queryRows(ctx, q, query, nil, func(rows *sql.Rows) error {
+		return rows.Scan(&s)
+	})
+	return s.String, err
+}
+
+var typePattern = regexp.MustCompile(`^([^(]*?)\s*\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)(.*)$`)
+
+// parseType 将 varchar(32)、numeric(10,2) 形式的类型解析为类型名和长度、精度
+func parseType(typ string) (dataType string, length, precision, scale int64) {
+	m := typePattern.FindStringSubmatch(typ)
+	if m == nil {
+		return strings.ToLower(strings.TrimSpace(typ)), 0, 0, 0
+	}
+	dataType = strings.ToLower(strings.TrimSpace(m[1] + m[4]))
+	a, _ := strconv.ParseInt(m[2], 10, 64)
+	b, _ := strconv.ParseInt(m[3], 10, 64)
+	if isCharType(dataType) {
+		return dataType, a, 0, 0
+	}
+	return dataType, 0, a, b
+}
+
+func isCharType(dataType string) bool {
+	return strings.Contains(dataType, "char") || strings.Contains(dataType, "binary") ||
+		strings.Contains(dataType, "bit") || strings.Contains(dataType, "text")
+}
+
+// namedColumns 将按名字和序号排好的 (name, column) 行合并为每个名字一组列
+type namedColumns struct {
+	names   []string
+	columns map[string][]string
+}
+
+func (n *namedColumns) add(name, column string) {
+	if n.columns == nil {
+		n.columns = make(map[string][]string)
+	}
+	if _, ok := n.columns[name]; !ok {
+		n.names = append(n.names, name)
+	}
+	n.columns[name] = append(n.columns[name], column)
+}
+
+// ruleName 将外键规则统一为大写并用空格分隔
+func ruleName(rule string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(rule), "_", " "))
+}
+
+// nonEmpty 去掉表达式索引中为空的列名
+func nonEmpty(columns []string) []string {
+	out := columns[:0]
+	for _, c := range columns {
+		if c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
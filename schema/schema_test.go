@@ -0,0 +1,208 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	_ "github.com/kweaver-ai/proton-rds-sdk-go/driver/sqlite"
+	"github.com/kweaver-ai/proton-rds-sdk-go/sqlx"
+)
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		typ       string
+		dataType  string
+		length    int64
+		precision int64
+		scale     int64
+	}{
+		{"varchar(32)", "varchar", 32, 0, 0},
+		{"character varying(64)", "character varying", 64, 0, 0},
+		{"numeric(10,2)", "numeric", 0, 10, 2},
+		{"DECIMAL(8, 3)", "decimal", 0, 8, 3},
+		{"timestamp(6) without time zone", "timestamp without time zone", 0, 6, 0},
+		{"INTEGER", "integer", 0, 0, 0},
+		{"text", "text", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			dataType, length, precision, scale := parseType(tt.typ)
+			assert.Equal(t, tt.dataType, dataType)
+			assert.Equal(t, tt.length, length)
+			assert.Equal(t, tt.precision, precision)
+			assert.Equal(t, tt.scale, scale)
+		})
+	}
+}
+
+func TestAttnumColumns(t *testing.T) {
+	names := map[int]string{1: "f_id", 2: "f_name", 3: "f_age"}
+	assert.Equal(t, []string{"f_name", "f_id"}, attnumColumns("2 1", names))
+	assert.Equal(t, []string{"f_id", "f_age"}, attnumColumns("{1,3}", names))
+	assert.Equal(t, []string{"f_age"}, attnumColumns("0 3", names))
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, "oracle")
+	assert.True(t, errors.Is(err, rds.ErrUnsupportedDBType))
+
+	for _, dbType := range []string{"mysql", "tidb", "dm8", "kingbase", "postgres", "opengauss", "sqlite3"} {
+		i, err := New(nil, dbType)
+		assert.Nil(t, err, dbType)
+		assert.NotNil(t, i, dbType)
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.NewDB(&sqlx.DBConfig{
+		User:     "user",
+		Password: "pwd",
+		Host:     "localhost",
+		Database: filepath.Join(t.TempDir(), "test.db"),
+		DBType:   "sqlite",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		"CREATE TABLE `t_dept` (`f_id` INTEGER PRIMARY KEY, `f_name` VARCHAR(32) NOT NULL)",
+		"CREATE TABLE `t_user` (`f_org` INTEGER, `f_id` INTEGER, `f_name` VARCHAR(64) NOT NULL DEFAULT '', " +
+			"`f_salary` DECIMAL(10,2), `f_dept` INTEGER REFERENCES `t_dept` ON DELETE CASCADE, PRIMARY KEY (`f_org`, `f_id`))",
+		"CREATE UNIQUE INDEX `idx_name` ON `t_user` (`f_name`, `f_org`)",
+		"CREATE VIEW `v_user` AS SELECT `f_id` FROM `t_user`",
+	} {
+		_, err = db.Exec(stmt)
+		assert.Nil(t, err)
+	}
+
+	i, err := NewFromDB(ctx, db)
+	assert.Nil(t, err)
+
+	tables, err := i.Tables(ctx, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []Table{
+		{Schema: "main", Name: "t_dept"},
+		{Schema: "main", Name: "t_user"},
+		{Schema: "main", Name: "v_user", View: true},
+	}, tables)
+
+	table, err := i.Describe(ctx, "", "t_user")
+	assert.Nil(t, err)
+	empty := "''"
+	assert.Equal(t, []Column{
+		{Name: "f_org", Type: "INTEGER", DataType: "integer", Position: 1},
+		{Name: "f_id", Type: "INTEGER", DataType: "integer", Position: 2},
+		{Name: "f_name", Type: "VARCHAR(64)", DataType: "varchar", Length: 64, Default: &empty, Position: 3},
+		{Name: "f_salary", Type: "DECIMAL(10,2)", DataType: "decimal", Nullable: true, Precision: 10, Scale: 2, Position: 4},
+		{Name: "f_dept", Type: "INTEGER", DataType: "integer", Nullable: true, Position: 5},
+	}, table.Columns)
+	assert.Equal(t, &PrimaryKey{Columns: []string{"f_org", "f_id"}}, table.PrimaryKey)
+	assert.Equal(t, []Index{
+		{Name: "idx_name", Columns: []string{"f_name", "f_org"}, Unique: true},
+		{Name: "sqlite_autoindex_t_user_1", Columns: []string{"f_org", "f_id"}, Unique: true, Primary: true},
+	}, table.Indexes)
+	assert.Equal(t, []ForeignKey{
+		{Columns: []string{"f_dept"}, RefSchema: "main", RefTable: "t_dept", RefColumns: []string{"f_id"},
+			OnUpdate: "NO ACTION", OnDelete: "CASCADE"},
+	}, table.ForeignKeys)
+
+	columns, err := i.Columns(ctx, "", "t_dept")
+	assert.Nil(t, err)
+	assert.True(t, columns[0].AutoIncrement)
+
+	table, err = i.Describe(ctx, "", "t_none")
+	assert.Nil(t, err)
+	assert.Nil(t, table)
+}
+
+func TestMySQL(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	i, err := New(db, "MYSQL")
+	assert.Nil(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DATABASE()")).
+		WillReturnRows(sqlmock.NewRows([]string{"DATABASE()"}).AddRow("test"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?")).
+		WithArgs("test", "t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_DEFAULT",
+			"CHARACTER_MAXIMUM_LENGTH", "NUMERIC_PRECISION", "NUMERIC_SCALE", "EXTRA", "ORDINAL_POSITION", "COLUMN_COMMENT"}).
+			AddRow("f_id", "bigint", "bigint", "NO", nil, nil, 19, 0, "auto_increment", 1, "主键").
+			AddRow("f_name", "varchar", "varchar(32)", "YES", "a", 32, nil, nil, "", 2, ""))
+	columns, err := i.Columns(ctx, "", "t_user")
+	assert.Nil(t, err)
+	def := "a"
+	assert.Equal(t, []Column{
+		{Name: "f_id", Type: "bigint", DataType: "bigint", Precision: 19, AutoIncrement: true, Position: 1, Comment: "主键"},
+		{Name: "f_name", Type: "varchar(32)", DataType: "varchar", Nullable: true, Default: &def, Length: 32, Position: 2},
+	}, columns)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.STATISTICS")).
+		WithArgs("test", "t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"INDEX_NAME", "NON_UNIQUE", "COLUMN_NAME"}).
+			AddRow("PRIMARY", 0, "f_id").
+			AddRow("idx_name", 1, "f_name").
+			AddRow("idx_name", 1, "f_id"))
+	indexes, err := i.Indexes(ctx, "test", "t_user")
+	assert.Nil(t, err)
+	assert.Equal(t, []Index{
+		{Name: "PRIMARY", Columns: []string{"f_id"}, Unique: true, Primary: true},
+		{Name: "idx_name", Columns: []string{"f_name", "f_id"}},
+	}, indexes)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.KEY_COLUMN_USAGE k")).
+		WithArgs("test", "t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_SCHEMA",
+			"REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "UPDATE_RULE", "DELETE_RULE"}).
+			AddRow("fk_dept", "f_dept", "test", "t_dept", "f_id", "NO ACTION", "SET NULL"))
+	fks, err := i.ForeignKeys(ctx, "test", "t_user")
+	assert.Nil(t, err)
+	assert.Equal(t, []ForeignKey{{Name: "fk_dept", Columns: []string{"f_dept"}, RefSchema: "test", RefTable: "t_dept",
+		RefColumns: []string{"f_id"}, OnUpdate: "NO ACTION", OnDelete: "SET NULL"}}, fks)
+
+	mock.ExpectQuery("FROM information_schema.TABLES").WillReturnError(sql.ErrConnDone)
+	_, err = i.Tables(ctx, "test")
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestKingbase(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	i, err := New(db, "KDB9")
+	assert.Nil(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM sys_index x")).
+		WithArgs("public", "t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"relname", "indisunique", "indisprimary", "indkey"}).
+			AddRow("idx_name", false, false, "2 1").
+			AddRow("t_user_pkey", true, true, "1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT a.attnum, a.attname FROM sys_attribute a")).
+		WithArgs("public", "t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"attnum", "attname"}).AddRow(1, "f_id").AddRow(2, "f_name"))
+	indexes, err := i.Indexes(ctx, "public", "t_user")
+	assert.Nil(t, err)
+	assert.Equal(t, []Index{
+		{Name: "idx_name", Columns: []string{"f_name", "f_id"}},
+		{Name: "t_user_pkey", Columns: []string{"f_id"}, Unique: true, Primary: true},
+	}, indexes)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
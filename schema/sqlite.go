@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// sqliteCatalog 通过 sqlite_master 和 PRAGMA 表值函数查询 SQLite 的表结构，schema 为 main、temp 或 ATTACH 的库名
+type sqliteCatalog struct {
+	q Queryer
+}
+
+func (c sqliteCatalog) currentSchema(ctx context.Context) (string, error) {
+	return "main", nil
+}
+
+func (c sqliteCatalog) tables(ctx context.Context, schema string) ([]Table, error) {
+	query := "SELECT name, type FROM " + quoteSQLite(schema) + ".sqlite_master " +
+		"WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY name"
+	var tables []Table
+	err := queryRows(ctx, c.q, query, nil, func(rows *sql.Rows) error {
+		var t Table
+		var typ string
+		if err := rows.Scan(&t.Name, &typ); err != nil {
+			return err
+		}
+		t.Schema = schema
+		t.View = typ == "view"
+		tables = append(tables, t)
+		return nil
+	})
+	return tables, err
+}
+
+func (c sqliteCatalog) columns(ctx context.Context, schema, table string) ([]Column, error) {
+	const query = `SELECT name, type, "notnull", dflt_value, pk, cid FROM pragma_table_info(?, ?) ORDER BY cid`
+	var columns []Column
+	var pks int
+	err := queryRows(ctx, c.q, query, []interface{}{table, schema}, func(rows *sql.Rows) error {
+		var col Column
+		var notNull bool
+		var pk int
+		var def sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &def, &pk, &col.Position); err != nil {
+			return err
+		}
+		col.Position++
+		col.DataType, col.Length, col.Precision, col.Scale = parseType(col.Type)
+		col.Nullable = !notNull && pk == 0
+		if def.Valid {
+			col.Default = &def.String
+		}
+		// 单列的 INTEGER PRIMARY KEY 为 rowid 的别名，自动分配
+		col.AutoIncrement = pk == 1 && strings.EqualFold(col.Type, "INTEGER")
+		if pk > 0 {
+			pks++
+		}
+		columns = append(columns, col)
+		return nil
+	})
+	if pks > 1 {
+		for i := range columns {
+			columns[i].AutoIncrement = false
+		}
+	}
+	return columns, err
+}
+
+func (c sqliteCatalog) primaryKey(ctx context.Context, schema, table string) (*PrimaryKey, error) {
+	const query = `SELECT name FROM pragma_table_info(?, ?) WHERE pk > 0 ORDER BY pk`
+	var pk *PrimaryKey
+	err := queryRows(ctx, c.q, query, []interface{}{table, schema}, func(rows *sql.Rows) error {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		if pk == nil {
+			pk = &PrimaryKey{}
+		}
+		pk.Columns = append(pk.Columns, column)
+		return nil
+	})
+	return pk, err
+}
+
+func (c sqliteCatalog) indexes(ctx context.Context, schema, table string) ([]Index, error) {
+	const query = `SELECT l.name, l."unique", l.origin, i.name FROM pragma_index_list(?, ?) l ` +
+		`JOIN pragma_index_info(l.name, ?) i ORDER BY l.name, i.seqno`
+	var named namedColumns
+	unique := make(map[string]bool)
+	primary := make(map[string]bool)
+	err := queryRows(ctx, c.q, query, []interface{}{table, schema, schema}, func(rows *sql.Rows) error {
+		var name, origin string
+		var isUnique bool
+		var column sql.NullString
+		if err := rows.Scan(&name, &isUnique, &origin, &column); err != nil {
+			return err
+		}
+		unique[name] = isUnique
+		primary[name] = origin == "pk"
+		named.add(name, column.String)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]Index, len(named.names))
+	for i, name := range named.names {
+		indexes[i] = Index{Name: name, Columns: nonEmpty(named.columns[name]), Unique: unique[name], Primary: primary[name]}
+	}
+	return indexes, nil
+}
+
+func (c sqliteCatalog) foreignKeys(ctx context.Context, schema, table string) ([]ForeignKey, error) {
+	const query = `SELECT id, "table", "from", "to", on_update, on_delete FROM pragma_foreign_key_list(?, ?) ORDER BY id, seq`
+	var fks []ForeignKey
+	lastID := -1
+	err := queryRows(ctx, c.q, query, []interface{}{table, schema}, func(rows *sql.Rows) error {
+		var id int
+		var refTable, column, onUpdate, onDelete string
+		var refColumn sql.NullString
+		if err := rows.Scan(&id, &refTable, &column, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		// SQLite 不记录外键名
+		if id != lastID {
+			fks = append(fks, ForeignKey{RefSchema: schema, RefTable: refTable,
+				OnUpdate: ruleName(onUpdate), OnDelete: ruleName(onDelete)})
+			lastID = id
+		}
+		fk := &fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn.String)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// REFERENCES t 未指定列时引用 t 的主键
+	for i := range fks {
+		if fks[i].RefColumns[0] != "" {
+			continue
+		}
+		pk, err := c.primaryKey(ctx, schema, fks[i].RefTable)
+		if err != nil {
+			return nil, err
+		}
+		if pk != nil {
+			fks[i].RefColumns = pk.Columns
+		}
+	}
+	return fks, nil
+}
+
+func quoteSQLite(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
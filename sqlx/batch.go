@@ -0,0 +1,297 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+const (
+	// defaultMaxAllowedPacket 为查询不到 max_allowed_packet 时使用的 MySQL 默认值
+	defaultMaxAllowedPacket = 4 << 20
+	// arrayBatchRows 为 DM8 数组绑定每批的行数
+	arrayBatchRows = 10000
+	// copyBatchRows 为 Kingbase COPY 每个事务的行数
+	copyBatchRows = 10000
+)
+
+// BatchResult 为批量插入的结果
+type BatchResult struct {
+	// Inserted 为成功插入的行数
+	Inserted int64
+	// Errors 为插入失败的各批数据的错误，按批次顺序排列
+	Errors []*BatchError
+}
+
+// Err 返回各批错误合并后的错误，全部成功时为 nil
+func (r BatchResult) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+// BatchError 为一批数据插入失败的错误，该批数据为 rows[Start:End]
+type BatchError struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("sqlx: batch insert rows [%d, %d): %v", e.Start, e.End, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchInsert 按主库的数据库类型选择最快的方式批量插入，rows 中每行的值与 columns 一一对应：
+// MySQL/MariaDB/TiDB/GoldenDB/OceanBase 为多行 VALUES 的 INSERT，每条语句的大小不超过 max_allowed_packet，
+// DM8 为数组绑定（驱动不接受时为多行 VALUES），Kingbase/PostgreSQL/openGauss 为 gokb 的 COPY FROM STDIN，每批在一个事务中执行，
+// 其它数据库为多行 VALUES 的 INSERT，每条语句的参数个数不超过上限。
+// 各批独立执行，某一批失败时继续插入其余的批次，返回的错误为各批错误的合并，
+// 可用 driver.Classify 判断错误类别，BatchResult 中为成功插入的行数和各批的错误
+func BatchInsert(ctx context.Context, db *DB, table string, columns []string, rows [][]any) (BatchResult, error) {
+	var result BatchResult
+	if len(rows) == 0 {
+		return result, nil
+	}
+	if len(columns) == 0 {
+		return result, errors.New("sqlx: batch insert requires at least one column")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return result, fmt.Errorf("sqlx: batch insert row %d has %d values, want %d", i, len(row), len(columns))
+		}
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	var chunks [][2]int
+	var insert func(ctx context.Context, rows [][]any) (int64, error)
+	switch d.Name() {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		chunks = packetChunks(rows, db.maxParams(ctx), db.maxAllowedPacket(ctx))
+		insert = db.insertValues(d, table, columns)
+	case "DM8":
+		if db.arrayBinding(ctx, rows[0]) {
+			chunks = rowChunks(len(rows), arrayBatchRows)
+			insert = db.insertArray(d, table, columns)
+		} else {
+			// 驱动不接受数组绑定的参数时退回到多行 VALUES
+			chunks = rowChunks(len(rows), max(db.maxParams(ctx)/len(columns), 1))
+			insert = db.insertValues(d, table, columns)
+		}
+	case "KDB9", "POSTGRES", "OPENGAUSS":
+		chunks = rowChunks(len(rows), copyBatchRows)
+		insert = db.copyIn(d, table, columns)
+	default:
+		chunks = rowChunks(len(rows), max(db.maxParams(ctx)/len(columns), 1))
+		insert = db.insertValues(d, table, columns)
+	}
+
+	for _, c := range chunks {
+		if err := ctx.Err(); err != nil {
+			result.Errors = append(result.Errors, &BatchError{Start: c[0], End: len(rows), Err: err})
+			break
+		}
+		n, err := insert(ctx, rows[c[0]:c[1]])
+		if err != nil {
+			result.Errors = append(result.Errors, &BatchError{Start: c[0], End: c[1], Err: err})
+			continue
+		}
+		result.Inserted += n
+	}
+	return result, result.Err()
+}
+
+// insertValues 返回用一条多行 VALUES 的 INSERT 插入一批数据的函数
+func (db *DB) insertValues(d rds.Dialect, table string, columns []string) func(context.Context, [][]any) (int64, error) {
+	prefix := insertPrefix(d, table, columns)
+	return func(ctx context.Context, rows [][]any) (int64, error) {
+		var b strings.Builder
+		b.WriteString(prefix)
+		args := make([]any, 0, len(rows)*len(columns))
+		for r, row := range rows {
+			if r > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('(')
+			for c := range columns {
+				if c > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(d.Placeholder(r*len(columns) + c + 1))
+			}
+			b.WriteByte(')')
+			args = append(args, row...)
+		}
+		res, err := db.writer.ExecContext(ctx, b.String(), args...)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+}
+
+// insertArray 返回用 DM 驱动的数组绑定插入一批数据的函数，
+// 绑定的参数为 [][]interface{}，由驱动按行批量执行（DSN 的 batchType 为默认值 1）
+func (db *DB) insertArray(d rds.Dialect, table string, columns []string) func(context.Context, [][]any) (int64, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := insertPrefix(d, table, columns) + "(" + placeholders + ")"
+	return func(ctx context.Context, rows [][]any) (int64, error) {
+		batch := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			batch[i] = row
+		}
+		res, err := db.writer.ExecContext(ctx, query, batch)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+}
+
+// arrayBinding 判断主库连接的驱动是否接受数组绑定的 [][]interface{} 参数，row 为用于检查的一行数据
+func (db *DB) arrayBinding(ctx context.Context, row []any) bool {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	ok := false
+	conn.Raw(func(dc any) error {
+		if nvc, isChecker := rds.UnwrapConn(dc).(driver.NamedValueChecker); isChecker {
+			ok = nvc.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: [][]interface{}{append([]any(nil), row...)}}) == nil
+		}
+		return nil
+	})
+	return ok
+}
+
+// copyIn 返回用 gokb 的 COPY FROM STDIN 在一个事务中插入一批数据的函数
+func (db *DB) copyIn(d rds.Dialect, table string, columns []string) func(context.Context, [][]any) (int64, error) {
+	query := "COPY " + d.QuoteIdentifier(table) + " (" + quoteNames(d, columns) + ") FROM STDIN"
+	return func(ctx context.Context, rows [][]any) (int64, error) {
+		tx, err := db.writer.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range rows {
+			if _, err = stmt.ExecContext(ctx, row...); err != nil {
+				stmt.Close()
+				return 0, err
+			}
+		}
+		// 不带参数的 Exec 结束 COPY 并返回服务端的错误
+		if _, err = stmt.ExecContext(ctx); err != nil {
+			stmt.Close()
+			return 0, err
+		}
+		if err = stmt.Close(); err != nil {
+			return 0, err
+		}
+		if err = tx.Commit(); err != nil {
+			return 0, err
+		}
+		return int64(len(rows)), nil
+	}
+}
+
+// insertPrefix 返回 INSERT INTO table (columns) VALUES 部分
+func insertPrefix(d rds.Dialect, table string, columns []string) string {
+	return "INSERT INTO " + d.QuoteIdentifier(table) + " (" + quoteNames(d, columns) + ") VALUES "
+}
+
+func quoteNames(d rds.Dialect, names []string) string {
+	s := make([]string, len(names))
+	for i, n := range names {
+		s[i] = d.QuoteIdentifier(n)
+	}
+	return strings.Join(s, ", ")
+}
+
+// maxParams 返回主库一条语句的参数个数上限，查询不到时使用各数据库上限中的最小值
+func (db *DB) maxParams(ctx context.Context) int {
+	if c, err := db.Capabilities(ctx); err == nil && c.MaxParams > 0 {
+		return c.MaxParams
+	}
+	return minMaxParams
+}
+
+// maxAllowedPacket 查询主库的 max_allowed_packet，查询失败时使用 MySQL 的默认值
+func (db *DB) maxAllowedPacket(ctx context.Context) int {
+	q, ok := db.writer.(interface {
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	})
+	if !ok {
+		return defaultMaxAllowedPacket
+	}
+	var n int
+	if err := q.QueryRowContext(ctx, "SELECT @@max_allowed_packet").Scan(&n); err != nil || n <= 0 {
+		return defaultMaxAllowedPacket
+	}
+	return n
+}
+
+// rowChunks 将 n 行按每批 size 行拆分为 [start, end) 区间
+func rowChunks(n, size int) [][2]int {
+	var chunks [][2]int
+	for start := 0; start < n; start += size {
+		chunks = append(chunks, [2]int{start, min(start+size, n)})
+	}
+	return chunks
+}
+
+// packetChunks 按估算的报文大小和参数个数拆分，每批的大小不超过 max_allowed_packet 的一半，
+// 为语句文本、驱动开启 interpolateParams 时的转义留出余量，单行超过时该行单独为一批
+func packetChunks(rows [][]any, maxParams, packet int) [][2]int {
+	budget := packet / 2
+	var chunks [][2]int
+	start, size := 0, 0
+	for i, row := range rows {
+		n := rowSize(row)
+		params := (i - start + 1) * len(row)
+		if i > start && (size+n > budget || params > maxParams) {
+			chunks = append(chunks, [2]int{start, i})
+			start, size = i, 0
+		}
+		size += n
+	}
+	return append(chunks, [2]int{start, len(rows)})
+}
+
+// rowSize 估算一行数据在语句中占用的字节数，每个值另加占位符和类型信息的开销
+func rowSize(row []any) int {
+	size := 0
+	for _, v := range row {
+		size += 8
+		switch v := v.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		case time.Time:
+			size += 24
+		case nil:
+		default:
+			size += 16
+		}
+	}
+	return size
+}
@@ -0,0 +1,169 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+func TestBatchInsert(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY, `f_name` VARCHAR(32))")
+	assert.Nil(t, err)
+
+	rows := make([][]any, 20000)
+	for i := range rows {
+		rows[i] = []any{i + 1, "user"}
+	}
+	// SQLite 的参数上限为 32766，每批 16383 行
+	result, err := BatchInsert(ctx, db, "t_user", []string{"f_id", "f_name"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20000), result.Inserted)
+	assert.Empty(t, result.Errors)
+
+	// 第一批主键冲突，第二批仍然插入
+	rows = make([][]any, 20000)
+	for i := range rows {
+		rows[i] = []any{i + 20000, nil}
+	}
+	result, err = BatchInsert(ctx, db, "t_user", []string{"f_id", "f_name"}, rows)
+	assert.Equal(t, rds.UniqueViolation, rds.Classify(err))
+	assert.Equal(t, int64(20000-16383), result.Inserted)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, 0, result.Errors[0].Start)
+		assert.Equal(t, 16383, result.Errors[0].End)
+	}
+
+	var count int
+	assert.Nil(t, db.QueryRow("SELECT COUNT(*) FROM `t_user`").Scan(&count))
+	assert.Equal(t, 20000+20000-16383, count)
+
+	_, err = BatchInsert(ctx, db, "t_user", []string{"f_id", "f_name"}, [][]any{{1}})
+	assert.NotNil(t, err)
+	result, err = BatchInsert(ctx, db, "t_user", []string{"f_id"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), result.Inserted)
+}
+
+func TestBatchInsertMySQL(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+	d, _ := rds.DialectFor("MYSQL")
+	db.SetDialect(d)
+
+	// max_allowed_packet 为 128 时每批的大小上限为 64，每行 2*8+16+8=40 字节
+	mock.ExpectQuery(`SELECT @@max_allowed_packet`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(128))
+	mock.ExpectExec("INSERT INTO `t_user` \\(`f_id`, `f_name`\\) VALUES \\(\\?, \\?\\)$").
+		WithArgs(1, "abcdefgh").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `t_user` \\(`f_id`, `f_name`\\) VALUES \\(\\?, \\?\\)$").
+		WithArgs(2, "abcdefgh").
+		WillReturnError(errors.New("boom"))
+	result, err := BatchInsert(context.Background(), db, "t_user", []string{"f_id", "f_name"},
+		[][]any{{1, "abcdefgh"}, {2, "abcdefgh"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(1), result.Inserted)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, 1, result.Errors[0].Start)
+		assert.Equal(t, 2, result.Errors[0].End)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchInsertKingbase(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+	db.SetDialect(rds.KingbaseDialect("pg"))
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(`COPY "t_user" \("f_id", "f_name"\) FROM STDIN`)
+	prep.ExpectExec().WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithArgs(2, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	result, err := BatchInsert(context.Background(), db, "t_user", []string{"f_id", "f_name"}, [][]any{{1, "a"}, {2, nil}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Inserted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// arrayConverter 模拟 DM 驱动接受数组绑定的参数检查
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v any) (driver.Value, error) {
+	if _, ok := v.([][]interface{}); ok {
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+// newDMMock 返回方言为 DM8 的 sqlmock 连接池，array 为 true 时驱动接受 [][]interface{} 参数
+func newDMMock(t *testing.T, array bool) (*DB, sqlmock.Sqlmock) {
+	var converter driver.ValueConverter = driver.DefaultParameterConverter
+	if array {
+		converter = arrayConverter{}
+	}
+	sqlDB, mock, err := sqlmock.New(sqlmock.ValueConverterOption(converter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	db := &DB{reader: sqlDB, writer: sqlDB}
+	d, _ := rds.DialectFor("DM8")
+	db.SetDialect(d)
+	return db, mock
+}
+
+func TestBatchInsertDM(t *testing.T) {
+	rows := [][]any{{1, "a"}, {2, nil}}
+
+	db, mock := newDMMock(t, true)
+	mock.ExpectExec(`INSERT INTO "t_user" \("f_id", "f_name"\) VALUES \(\?, \?\)$`).
+		WithArgs([][]interface{}{{1, "a"}, {2, nil}}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	result, err := BatchInsert(context.Background(), db, "t_user", []string{"f_id", "f_name"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Inserted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// 驱动不接受数组绑定时退回到多行 VALUES
+	db, mock = newDMMock(t, false)
+	mock.ExpectExec(`INSERT INTO "t_user" \("f_id", "f_name"\) VALUES \(\?, \?\), \(\?, \?\)$`).
+		WithArgs(1, "a", 2, nil).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	result, err = BatchInsert(context.Background(), db, "t_user", []string{"f_id", "f_name"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Inserted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPacketChunks(t *testing.T) {
+	row := []any{1, strings.Repeat("a", 76)} // 8+16+8+76=108 字节
+	rows := [][]any{row, row, row, row, row}
+	tests := []struct {
+		name      string
+		maxParams int
+		packet    int
+		want      [][2]int
+	}{
+		{"all", 100, 1 << 20, [][2]int{{0, 5}}},
+		{"params", 4, 1 << 20, [][2]int{{0, 2}, {2, 4}, {4, 5}}},
+		{"packet", 100, 500, [][2]int{{0, 2}, {2, 4}, {4, 5}}},
+		{"oversized", 100, 100, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, packetChunks(rows, tt.maxParams, tt.packet))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// Capabilities 返回主库支持的特性和限制，第一次调用时查询服务端版本并缓存
+func (db *DB) Capabilities(ctx context.Context) (rds.Capabilities, error) {
+	db.capsMu.Lock()
+	defer db.capsMu.Unlock()
+	if db.caps != nil {
+		return *db.caps, nil
+	}
+	w, ok := db.writer.(*sql.DB)
+	if !ok {
+		return rds.Capabilities{}, errNoDialect
+	}
+	c, err := rds.CapabilitiesOf(ctx, w)
+	if err != nil {
+		return rds.Capabilities{}, err
+	}
+	db.caps = &c
+	return c, nil
+}
@@ -3,10 +3,15 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
 )
 
 /*
@@ -66,6 +71,13 @@ type writer interface {
 type DB struct {
 	reader
 	writer
+
+	dialectMu sync.Mutex
+	dialect   rds.Dialect
+	capsMu    sync.Mutex
+	caps      *rds.Capabilities
+	retry     atomic.Pointer[RetryPolicy]
+	in        atomic.Pointer[InOptions]
 }
 
 // ParseHost 判定host是否为IPv6格式，如果是，返回 [host]
@@ -103,6 +115,10 @@ func NewDB(dbConfig *DBConfig) (*DB, error) {
 	if dbConfig.Loc != "" {
 		query.Set("loc", dbConfig.Loc)
 	}
+	// DBType 只对 proton-rds 系列驱动生效，未设置时按驱动名或 DB_TYPE 环境变量选择
+	if dbConfig.DBType != "" && strings.HasPrefix(driverName, "proton-rds") {
+		query.Set("dbtype", dbConfig.DBType)
+	}
 	dbConfig.Host = ParseHost(dbConfig.Host)
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
 		dbConfig.User,
@@ -159,6 +175,17 @@ func NewDB(dbConfig *DBConfig) (*DB, error) {
 	}, nil
 }
 
+// Conn 从主库连接池取得一个独占的连接，用于会话级的锁和设置，用完需 Close
+func (db *DB) Conn(ctx context.Context) (*sql.Conn, error) {
+	w, ok := db.writer.(interface {
+		Conn(ctx context.Context) (*sql.Conn, error)
+	})
+	if !ok {
+		return nil, errors.New("sqlx: the writer does not support dedicated connections")
+	}
+	return w.Conn(ctx)
+}
+
 // FOR UT
 func (db *DB) Close() error {
 	db.reader.Close()
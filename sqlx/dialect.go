@@ -0,0 +1,37 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+var errNoDialect = errors.New("sqlx: cannot detect the backend of a connection pool not opened by proton-rds")
+
+// Dialect 返回主库连接池的 SQL 方言，第一次调用时识别并缓存，Kingbase 按连接的 database_mode 识别
+func (db *DB) Dialect(ctx context.Context) (rds.Dialect, error) {
+	db.dialectMu.Lock()
+	defer db.dialectMu.Unlock()
+	if db.dialect != nil {
+		return db.dialect, nil
+	}
+	w, ok := db.writer.(*sql.DB)
+	if !ok {
+		return nil, errNoDialect
+	}
+	d, err := rds.DialectOf(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	db.dialect = d
+	return d, nil
+}
+
+// SetDialect 指定连接池使用的 SQL 方言，用于自定义驱动和 sqlmock 等无法识别方言的场景
+func (db *DB) SetDialect(d rds.Dialect) {
+	db.dialectMu.Lock()
+	defer db.dialectMu.Unlock()
+	db.dialect = d
+}
@@ -0,0 +1,331 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrEmptyIn 为绑定到 ? 的切片为空，IN () 在各数据库中均为语法错误
+	ErrEmptyIn = errors.New("sqlx: empty slice bound to IN")
+	// ErrTooManyParams 为展开后的参数个数超过上限，且无法分批执行
+	ErrTooManyParams = errors.New("sqlx: too many parameters")
+)
+
+// InOptions 为切片参数的展开选项
+type InOptions struct {
+	// EmptyAsNull 为 true 时空切片展开为 NULL，IN (NULL) 不匹配任何行，否则返回 ErrEmptyIn
+	EmptyAsNull bool
+	// MaxParams 为一条语句的参数个数上限，为 0 时使用 Capabilities 的 MaxParams（DM8、Kingbase 为 65535）
+	MaxParams int
+}
+
+// In 将绑定到 ? 的切片参数（[]byte 和实现了 driver.Valuer 的类型除外）展开为对应个数的占位符，
+// 如 In("SELECT * FROM t WHERE id IN (?)", []int{1, 2, 3}) 返回 SELECT * FROM t WHERE id IN (?, ?, ?)，
+// 引号和注释中的 ? 不会被展开
+func In(query string, args ...interface{}) (string, []interface{}, error) {
+	return expandIn(query, args, InOptions{}, false)
+}
+
+// SetInExpansion 设置切片参数的自动展开，为空时不展开。开启后 Query、QueryRow、Exec、Get、Select
+// 及命名参数的方法均展开切片参数；参数个数超过上限时，Select 按最长的切片分批查询并合并结果，
+// Exec 在一个事务中分批执行并累加 RowsAffected，分批只用于最长的切片绑定在 col IN (?) 上、
+// 且语句没有 NOT IN、ORDER BY、LIMIT、DISTINCT、GROUP BY、聚合函数和 OR 的情况，否则与 Query、Get
+// 及命名参数的方法一样返回 ErrTooManyParams；QueryRow 不执行查询，展开时的错误由 row.Err() 和 Scan 返回
+func (db *DB) SetInExpansion(o *InOptions) {
+	db.in.Store(o)
+}
+
+// inQuery 为展开后的一条语句
+type inQuery struct {
+	query string
+	args  []interface{}
+}
+
+// expandChunks 按自动展开的设置展开切片参数，参数个数超过上限且 split 为 true 时按最长的切片拆分为多条语句，
+// 只有最长的切片绑定在 col IN (?) 上且语句没有排序、分页、去重、分组、聚合和 OR 时才能拆分
+func (db *DB) expandChunks(ctx context.Context, query string, args []interface{}, split bool) ([]inQuery, error) {
+	o := db.in.Load()
+	if o == nil || sliceArg(args) < 0 {
+		return []inQuery{{query, args}}, nil
+	}
+	// 先确定方言再扫描语句，无法识别方言的连接池按标准字符串处理
+	backslash := false
+	if d, err := db.Dialect(ctx); err == nil {
+		backslash = backslashEscapes(d)
+	}
+
+	q, expanded, err := expandIn(query, args, *o, backslash)
+	if err != nil {
+		return nil, err
+	}
+	limit := db.paramLimit(ctx, len(expanded), *o)
+	if limit <= 0 || len(expanded) <= limit {
+		return []inQuery{{q, expanded}}, nil
+	}
+	tooMany := fmt.Errorf("%w: %d exceeds %d", ErrTooManyParams, len(expanded), limit)
+
+	// 拆分最长的切片，其余参数在每条语句中保持不变
+	longest, n := -1, 0
+	for i, a := range args {
+		if v, ok := sliceValue(a); ok && v.Len() > n {
+			longest, n = i, v.Len()
+		}
+	}
+	size := limit - (len(expanded) - n)
+	if !split || size <= 0 || !chunkable(query, longest, backslash) {
+		return nil, tooMany
+	}
+	v, _ := sliceValue(args[longest])
+	var chunks []inQuery
+	for start := 0; start < n; start += size {
+		end := min(start+size, n)
+		chunkArgs := append([]interface{}(nil), args...)
+		chunkArgs[longest] = v.Slice(start, end).Interface()
+		q, expanded, err := expandIn(query, chunkArgs, *o, backslash)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, inQuery{q, expanded})
+	}
+	return chunks, nil
+}
+
+var (
+	// inPrefix 匹配占位符前的 col IN (，notInPrefix 匹配 NOT IN (
+	inPrefix    = regexp.MustCompile(`(?i)[\w.` + "`" + `"\]]\s+IN\s*\(\s*$`)
+	notInPrefix = regexp.MustCompile(`(?i)\bNOT\s+IN\s*\(\s*$`)
+	inSuffix    = regexp.MustCompile(`^\s*\)`)
+	// unchunkable 匹配分批执行后结果与一次执行不同的子句
+	unchunkable = regexp.MustCompile(`(?i)\b(ORDER\s+BY|GROUP\s+BY|HAVING|LIMIT|OFFSET|FETCH|TOP|DISTINCT|UNION|INTERSECT|EXCEPT|MINUS|OR)\b|` +
+		`\b(COUNT|SUM|AVG|MIN|MAX|GROUP_CONCAT|STRING_AGG|LISTAGG|WM_CONCAT)\s*\(`)
+)
+
+// chunkable 判断第 arg 个参数的切片能否拆分到多条语句中执行
+func chunkable(query string, arg int, backslash bool) bool {
+	code := maskLiterals(query, backslash)
+	if unchunkable.MatchString(code) {
+		return false
+	}
+	pos, n := -1, 0
+	scanPlaceholders(query, backslash, func(i int) error {
+		if n == arg {
+			pos = i
+		}
+		n++
+		return nil
+	})
+	if pos < 0 {
+		return false
+	}
+	return inPrefix.MatchString(code[:pos]) && !notInPrefix.MatchString(code[:pos]) && inSuffix.MatchString(code[pos+1:])
+}
+
+// maskLiterals 将引号中的内容和注释替换为空格，保留引号本身和各字符的位置
+func maskLiterals(query string, backslash bool) string {
+	b := []byte(query)
+	blank := func(from, to int) {
+		for j := from; j < to && j < len(b); j++ {
+			if b[j] != '\n' {
+				b[j] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			start := i + 1
+			for i++; i < len(query) && query[i] != c; i++ {
+				if backslash && query[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+			blank(start, i)
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			start := i
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			blank(start, i)
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			start := i
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+			blank(start, i+1)
+		}
+	}
+	return string(b)
+}
+
+// minMaxParams 为各数据库参数个数上限中的最小值，不超过时无需查询 Capabilities
+const minMaxParams = 32766
+
+// paramLimit 返回 n 个参数时需要检查的参数个数上限，0 表示不限制
+func (db *DB) paramLimit(ctx context.Context, n int, o InOptions) int {
+	limit := o.MaxParams
+	if limit <= 0 && n > minMaxParams {
+		if c, err := db.Capabilities(ctx); err == nil {
+			limit = c.MaxParams
+		}
+	}
+	return limit
+}
+
+// expandOne 展开切片参数，不能分批执行时超过上限返回 ErrTooManyParams
+func (db *DB) expandOne(ctx context.Context, query string, args []interface{}) (string, []interface{}, error) {
+	chunks, err := db.expandChunks(ctx, query, args, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return chunks[0].query, chunks[0].args, nil
+}
+
+// execChunks 在一个事务中执行分批的语句
+func (db *DB) execChunks(ctx context.Context, chunks []inQuery) (sql.Result, error) {
+	tx, err := db.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var result chunkResult
+	for _, c := range chunks {
+		res, err := tx.ExecContext(ctx, c.query, c.args...)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		result.rowsAffected += n
+		result.last = res
+	}
+	return result, tx.Commit()
+}
+
+// chunkResult 为分批执行的结果，RowsAffected 为各批之和，LastInsertId 取最后一批
+type chunkResult struct {
+	rowsAffected int64
+	last         sql.Result
+}
+
+func (r chunkResult) LastInsertId() (int64, error) {
+	return r.last.LastInsertId()
+}
+
+func (r chunkResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// sliceValue 判断参数是否为需要展开的切片
+func sliceValue(arg interface{}) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Value{}, false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// sliceArg 返回第一个切片参数的位置，没有时返回 -1
+func sliceArg(args []interface{}) int {
+	for i, a := range args {
+		if _, ok := sliceValue(a); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+func expandIn(query string, args []interface{}, o InOptions, backslash bool) (string, []interface{}, error) {
+	if sliceArg(args) < 0 {
+		return query, args, nil
+	}
+	var b strings.Builder
+	expanded := make([]interface{}, 0, len(args))
+	n := 0
+	start := 0
+	err := scanPlaceholders(query, backslash, func(i int) error {
+		if n >= len(args) {
+			return fmt.Errorf("sqlx: more placeholders than the %d arguments", len(args))
+		}
+		b.WriteString(query[start:i])
+		start = i + 1
+		arg := args[n]
+		n++
+		v, ok := sliceValue(arg)
+		if !ok {
+			b.WriteByte('?')
+			expanded = append(expanded, arg)
+			return nil
+		}
+		if v.Len() == 0 {
+			if !o.EmptyAsNull {
+				return ErrEmptyIn
+			}
+			b.WriteString("NULL")
+			return nil
+		}
+		for j := 0; j < v.Len(); j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('?')
+			expanded = append(expanded, v.Index(j).Interface())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if n != len(args) {
+		return "", nil, fmt.Errorf("sqlx: %d placeholders but %d arguments", n, len(args))
+	}
+	b.WriteString(query[start:])
+	return b.String(), expanded, nil
+}
+
+// scanPlaceholders 对引号、反引号和注释之外的每个 ? 调用 f
+func scanPlaceholders(query string, backslash bool, f func(i int) error) error {
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(query) && query[i] != c; i++ {
+				if backslash && query[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+		case c == '?':
+			if err := f(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type valuerSlice []int
+
+func (valuerSlice) Value() (driver.Value, error) { return "1,2", nil }
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		args  []interface{}
+		want  string
+		args2 []interface{}
+	}{
+		{"no slice", "SELECT * FROM t WHERE a = ?", []interface{}{1}, "SELECT * FROM t WHERE a = ?", []interface{}{1}},
+		{"slice", "SELECT * FROM t WHERE a = ? AND b IN (?)", []interface{}{1, []int{2, 3}},
+			"SELECT * FROM t WHERE a = ? AND b IN (?, ?)", []interface{}{1, 2, 3}},
+		{"strings", "SELECT * FROM t WHERE b IN (?) AND c IN (?)", []interface{}{[]string{"x"}, []any{"y", 4}},
+			"SELECT * FROM t WHERE b IN (?) AND c IN (?, ?)", []interface{}{"x", "y", 4}},
+		{"bytes and valuer", "SELECT * FROM t WHERE a = ? AND b = ? AND c IN (?)", []interface{}{[]byte("a"), valuerSlice{1}, []int{1}},
+			"SELECT * FROM t WHERE a = ? AND b = ? AND c IN (?)", []interface{}{[]byte("a"), valuerSlice{1}, 1}},
+		{"quoted", "SELECT '?', \"?\" /* ? */ FROM t -- ?\nWHERE b IN (?)", []interface{}{[]int{1, 2}},
+			"SELECT '?', \"?\" /* ? */ FROM t -- ?\nWHERE b IN (?, ?)", []interface{}{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, args, err := In(tt.query, tt.args...)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, q)
+			assert.Equal(t, tt.args2, args)
+		})
+	}
+
+	_, _, err := In("SELECT * FROM t WHERE b IN (?)", []int{})
+	assert.Equal(t, ErrEmptyIn, err)
+	q, args, err := expandIn("SELECT * FROM t WHERE b IN (?)", []interface{}{[]int{}}, InOptions{EmptyAsNull: true}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE b IN (NULL)", q)
+	assert.Empty(t, args)
+	_, _, err = In("SELECT * FROM t WHERE b IN (?) AND c = ?", []int{1})
+	assert.NotNil(t, err)
+	_, _, err = In("SELECT * FROM t WHERE b IN (?)", []int{1}, 2)
+	assert.NotNil(t, err)
+}
+
+func TestInExpansion(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE t_user (f_id INTEGER PRIMARY KEY, f_name VARCHAR(32))")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO t_user VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')")
+	assert.Nil(t, err)
+
+	_, err = db.Query("SELECT f_name FROM t_user WHERE f_id IN (?)", []int{1, 2})
+	assert.NotNil(t, err)
+
+	db.SetInExpansion(&InOptions{MaxParams: 3})
+	var names []string
+	assert.Nil(t, db.Select(ctx, &names, "SELECT f_name FROM t_user WHERE f_id IN (?) AND f_name <> ?",
+		[]int{1, 2, 3, 4, 5}, "c"))
+	assert.ElementsMatch(t, []string{"a", "b", "d", "e"}, names)
+
+	// 分批执行会改变结果的语句不拆分
+	for _, q := range []string{
+		"SELECT f_name FROM t_user WHERE f_id IN (?) ORDER BY f_id",
+		"SELECT f_name FROM t_user WHERE f_id IN (?) LIMIT 2",
+		"SELECT DISTINCT f_name FROM t_user WHERE f_id IN (?)",
+		"SELECT COUNT(*) FROM t_user WHERE f_id IN (?)",
+		"SELECT f_name FROM t_user WHERE f_id NOT IN (?)",
+		"SELECT f_name FROM t_user WHERE f_id IN (?) OR f_name = 'a'",
+		"SELECT f_name FROM t_user WHERE f_id = ANY (?)",
+	} {
+		names = nil
+		assert.ErrorIs(t, db.Select(ctx, &names, q, []int{1, 2, 3, 4, 5}), ErrTooManyParams, q)
+	}
+	_, err = db.Exec("DELETE FROM t_user WHERE f_id NOT IN (?)", []int{1, 2, 3, 4, 5})
+	assert.ErrorIs(t, err, ErrTooManyParams)
+	// 引号中的关键字不影响拆分
+	assert.Nil(t, db.Select(ctx, &names, "SELECT f_name FROM t_user WHERE f_id IN (?) AND f_name <> 'ORDER BY'",
+		[]int{1, 2, 3, 4, 5}))
+	assert.Len(t, names, 5)
+
+	var count int
+	assert.Nil(t, db.QueryRow("SELECT COUNT(*) FROM t_user WHERE f_id IN (?)", []int64{1, 2}).Scan(&count))
+	assert.Equal(t, 2, count)
+	_, err = db.Query("SELECT f_name FROM t_user WHERE f_id IN (?)", []int{1, 2, 3, 4})
+	assert.True(t, errors.Is(err, ErrTooManyParams))
+	row, err := db.QueryRowErr(ctx, "SELECT COUNT(*) FROM t_user WHERE f_id IN (?)", []int{1, 2, 3, 4})
+	assert.Nil(t, row)
+	assert.True(t, errors.Is(err, ErrTooManyParams))
+	row, err = db.QueryRowErr(ctx, "SELECT COUNT(*) FROM t_user WHERE f_id IN (?)", []int{1, 2, 3})
+	assert.Nil(t, err)
+	assert.Nil(t, row.Scan(&count))
+	assert.Equal(t, 3, count)
+	assert.ErrorIs(t, db.QueryRow("SELECT COUNT(*) FROM t_user WHERE f_id IN (?)", []int{1, 2, 3, 4}).Scan(&count), ErrTooManyParams)
+	assert.ErrorIs(t, db.QueryRow("SELECT COUNT(*) FROM t_user WHERE f_id IN (?)", []int{}).Err(), ErrEmptyIn)
+	_, err = db.Query("SELECT f_name FROM t_user WHERE f_id IN (?) AND f_name IN (?)", []int{1, 2}, []string{"a", "b"})
+	assert.True(t, errors.Is(err, ErrTooManyParams))
+
+	res, err := db.ExecContext(ctx, "UPDATE t_user SET f_name = ? WHERE f_id IN (?)", "x", []int{1, 2, 3, 4, 5})
+	assert.Nil(t, err)
+	n, err := res.RowsAffected()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), n)
+
+	_, err = db.Exec("DELETE FROM t_user WHERE f_id IN (?)", []int{})
+	assert.Equal(t, ErrEmptyIn, err)
+	db.SetInExpansion(&InOptions{EmptyAsNull: true})
+	res, err = db.Exec("DELETE FROM t_user WHERE f_id IN (?)", []int{})
+	assert.Nil(t, err)
+	n, _ = res.RowsAffected()
+	assert.Equal(t, int64(0), n)
+
+	db.SetInExpansion(nil)
+	_, err = db.Exec("DELETE FROM t_user WHERE f_id IN (?)", []int{1})
+	assert.NotNil(t, err)
+}
+
+func TestChunkable(t *testing.T) {
+	tests := []struct {
+		query     string
+		arg       int
+		backslash bool
+		want      bool
+	}{
+		{"DELETE FROM t WHERE `f_id` IN (?)", 0, false, true},
+		{"SELECT a FROM t WHERE b = ? AND t.c IN ( ? )", 1, false, true},
+		{"SELECT a FROM t WHERE b IN (?) AND c IN (?)", 0, false, true},
+		{"SELECT a FROM t WHERE b = ? AND c IN (?)", 0, false, false},
+		{"SELECT a FROM t WHERE c IN (?, 1)", 0, false, false},
+		{"SELECT a FROM t WHERE c not  in (?)", 0, false, false},
+		{"SELECT a FROM t WHERE d = 'x\\' OR ' AND c IN (?)", 0, true, true},
+		{"SELECT a FROM t WHERE d = 'x\\' OR ' AND c IN (?)", 0, false, false},
+		{"SELECT a FROM t /* ORDER BY */ WHERE c IN (?) -- LIMIT", 0, false, true},
+		{"SELECT max(a) FROM t WHERE c IN (?)", 0, false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, chunkable(tt.query, tt.arg, tt.backslash), tt.query)
+	}
+}
@@ -0,0 +1,294 @@
+package sqlx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// loadBatchRows 为逐行解析后批量插入时每批的行数
+const loadBatchRows = 10000
+
+// LoadOptions 为 LoadCSV 的数据格式，字段可用双引号包围，双引号内的双引号写两次
+type LoadOptions struct {
+	// Delimiter 为字段分隔符，为 0 时使用逗号，TSV 为 '\t'
+	Delimiter rune
+	// Header 为 true 时跳过第一行的列名
+	Header bool
+	// Null 为表示 NULL 的字段值，默认为空字符串，即空字段导入为 NULL。
+	// 与 COPY 的 CSV 格式相同，只有未被双引号包围的字段才与 Null 比较，"" 导入为空字符串
+	Null string
+}
+
+var loadSeq atomic.Int64
+
+// LoadCSV 将 r 中 CSV/TSV 格式的数据导入表中，每行的字段与 columns 一一对应，返回导入的行数。
+// Kingbase/PostgreSQL/openGauss 将 r 直接作为 COPY FROM STDIN 的数据，导入在一条语句中完成；
+// MySQL/MariaDB/TiDB/GoldenDB/OceanBase 在 Go 中按 COPY 的规则改写 r 后通过 go-sql-driver 注册并执行
+// LOAD DATA LOCAL INFILE，需要服务端开启 local_infile；其它数据库（如 DM8）在 Go 中逐行解析，
+// 每 10000 行通过 BatchInsert 插入一批，出错时之前的批次已经提交，返回的行数为已导入的行数
+func LoadCSV(ctx context.Context, db *DB, table string, columns []string, r io.Reader, opts LoadOptions) (int64, error) {
+	if len(columns) == 0 {
+		return 0, errors.New("sqlx: load requires at least one column")
+	}
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.Delimiter == '"' || opts.Delimiter == '\n' || opts.Delimiter == '\r' {
+		return 0, fmt.Errorf("sqlx: invalid load delimiter %q", opts.Delimiter)
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch d.Name() {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		return db.loadData(ctx, d, table, columns, r, opts)
+	case "KDB9", "POSTGRES", "OPENGAUSS":
+		return db.copyFrom(ctx, copyFromCSV(d, table, columns, opts), r)
+	}
+	return db.loadBatches(ctx, table, columns, r, opts)
+}
+
+// loadData 将 r 改写为 LOAD DATA 的格式并注册为 go-sql-driver 的 Reader 后执行 LOAD DATA LOCAL INFILE
+func (db *DB) loadData(ctx context.Context, d rds.Dialect, table string, columns []string, r io.Reader, opts LoadOptions) (int64, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(writeLoadData(pw, r, len(columns), opts))
+	}()
+
+	name := "proton-rds-load-" + strconv.FormatInt(loadSeq.Add(1), 10)
+	mysql.RegisterReaderHandler(name, func() io.Reader { return pr })
+	defer mysql.DeregisterReaderHandler(name)
+
+	res, err := db.writer.ExecContext(ctx, loadDataInfile(d, "Reader::"+name, table, columns, opts))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// writeLoadData 将 r 中的记录改写为 LOAD DATA 的格式：各行以 \n 结尾，字段均用双引号包围，
+// 未被双引号包围且等于 Null 的字段写为 NULL。LOAD DATA 把未包围的 NULL 读作 NULL，把包围的值读作字符串，
+// 与 COPY 区分 "" 和空字段的规则一致
+func writeLoadData(w io.Writer, r io.Reader, n int, opts LoadOptions) error {
+	s := newCSVScanner(r, opts.Delimiter)
+	if opts.Header {
+		if _, err := s.read(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	bw := bufio.NewWriter(w)
+	for {
+		record, err := s.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) != n {
+			return fmt.Errorf("sqlx: record on line %d has %d fields, want %d", s.line, len(record), n)
+		}
+		for i, f := range record {
+			if i > 0 {
+				bw.WriteRune(opts.Delimiter)
+			}
+			if !f.quoted && f.value == opts.Null {
+				bw.WriteString("NULL")
+				continue
+			}
+			bw.WriteString(`"` + strings.ReplaceAll(f.value, `"`, `""`) + `"`)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// loadDataInfile 返回 LOAD DATA LOCAL INFILE 语句，数据为 writeLoadData 改写后的格式，表头已在改写时跳过
+func loadDataInfile(d rds.Dialect, file, table string, columns []string, opts LoadOptions) string {
+	return fmt.Sprintf("LOAD DATA LOCAL INFILE %s INTO TABLE %s FIELDS TERMINATED BY %s OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '' LINES TERMINATED BY '\\n' (%s)",
+		d.QuoteLiteral(file), d.QuoteIdentifier(table), d.QuoteLiteral(string(opts.Delimiter)), quoteNames(d, columns))
+}
+
+// copyFromCSV 返回 CSV 格式的 COPY FROM STDIN 语句
+func copyFromCSV(d rds.Dialect, table string, columns []string, opts LoadOptions) string {
+	query := fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv, DELIMITER %s, NULL %s",
+		d.QuoteIdentifier(table), quoteNames(d, columns), d.QuoteLiteral(string(opts.Delimiter)), d.QuoteLiteral(opts.Null))
+	if opts.Header {
+		query += ", HEADER true"
+	}
+	return query + ")"
+}
+
+// copyFrom 在一个专用连接上通过 gokb 执行 COPY FROM STDIN
+func (db *DB) copyFrom(ctx context.Context, query string, r io.Reader) (int64, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	var n int64
+	err = conn.Raw(func(dc any) error {
+		c, ok := rds.UnwrapConn(dc).(interface {
+			CopyFrom(ctx context.Context, r io.Reader, query string) (int64, error)
+		})
+		if !ok {
+			return fmt.Errorf("sqlx: the connection %T does not support COPY FROM", dc)
+		}
+		n, err = c.CopyFrom(ctx, r, query)
+		return err
+	})
+	return n, err
+}
+
+// loadBatches 在 Go 中解析 r，每 loadBatchRows 行通过 BatchInsert 插入一批
+func (db *DB) loadBatches(ctx context.Context, table string, columns []string, r io.Reader, opts LoadOptions) (int64, error) {
+	s := newCSVScanner(r, opts.Delimiter)
+	if opts.Header {
+		if _, err := s.read(); err != nil {
+			if err == io.EOF {
+				return 0, nil
+			}
+			return 0, err
+		}
+	}
+
+	var inserted int64
+	rows := make([][]any, 0, loadBatchRows)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		result, err := BatchInsert(ctx, db, table, columns, rows)
+		inserted += result.Inserted
+		rows = rows[:0]
+		return err
+	}
+	for {
+		record, err := s.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return inserted, err
+		}
+		if len(record) != len(columns) {
+			return inserted, fmt.Errorf("sqlx: record on line %d has %d fields, want %d", s.line, len(record), len(columns))
+		}
+		row := make([]any, len(record))
+		for i, f := range record {
+			if f.quoted || f.value != opts.Null {
+				row[i] = f.value
+			}
+		}
+		rows = append(rows, row)
+		if len(rows) == loadBatchRows {
+			if err := flush(); err != nil {
+				return inserted, err
+			}
+		}
+	}
+	return inserted, flush()
+}
+
+// csvField 为 CSV 记录中的一个字段，quoted 为字段是否被双引号包围
+type csvField struct {
+	value  string
+	quoted bool
+}
+
+// csvScanner 按 COPY 的 CSV 规则读取记录。encoding/csv 不保留字段是否被双引号包围，无法区分 "" 和空字段
+type csvScanner struct {
+	r     *bufio.Reader
+	comma rune
+	// line 为已读取的最后一条记录结束的行号
+	line int
+}
+
+func newCSVScanner(r io.Reader, comma rune) *csvScanner {
+	return &csvScanner{r: bufio.NewReader(r), comma: comma}
+}
+
+// read 返回下一条记录，行尾为 \n 或 \r\n，双引号内可包含分隔符、换行和写两次的双引号，没有更多记录时返回 io.EOF
+func (s *csvScanner) read() ([]csvField, error) {
+	var (
+		record []csvField
+		b      strings.Builder
+		quoted bool
+		// inQuotes 为当前在双引号内，start 为当前位于字段开头
+		inQuotes, start = false, true
+	)
+	field := func() {
+		record = append(record, csvField{value: b.String(), quoted: quoted})
+		b.Reset()
+		quoted, start = false, true
+	}
+	for {
+		c, _, err := s.r.ReadRune()
+		if err == io.EOF {
+			if inQuotes {
+				return nil, fmt.Errorf("sqlx: unterminated quoted field on line %d", s.line+1)
+			}
+			if record == nil && start && !quoted {
+				return nil, io.EOF
+			}
+			s.line++
+			field()
+			return record, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c == '\n' {
+			s.line++
+		}
+		switch {
+		case inQuotes:
+			if c != '"' {
+				b.WriteRune(c)
+				continue
+			}
+			if next, _, err := s.r.ReadRune(); err == nil && next == '"' {
+				b.WriteRune('"')
+				continue
+			} else if err == nil {
+				s.r.UnreadRune()
+			}
+			inQuotes = false
+		case c == '"' && start:
+			inQuotes, quoted, start = true, true, false
+		case c == s.comma:
+			field()
+		case c == '\r':
+			if next, _, err := s.r.ReadRune(); err == nil && next == '\n' {
+				s.line++
+				field()
+				return record, nil
+			} else if err == nil {
+				s.r.UnreadRune()
+			}
+			b.WriteRune(c)
+			start = false
+		case c == '\n':
+			field()
+			return record, nil
+		default:
+			b.WriteRune(c)
+			start = false
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+func TestLoadCSV(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY, `f_name` VARCHAR(32))")
+	assert.Nil(t, err)
+
+	n, err := LoadCSV(ctx, db, "t_user", []string{"f_id", "f_name"},
+		strings.NewReader("id,name\n1,a\n2,\"b,\"\"c\"\"\"\n3,\n"), LoadOptions{Header: true})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	n, err = LoadCSV(ctx, db, "t_user", []string{"f_id", "f_name"},
+		strings.NewReader("4\t\\N\n5\td\n6\t\"\"\n"), LoadOptions{Delimiter: '\t', Null: `\N`})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	var names []*string
+	rows, err := db.Query("SELECT `f_name` FROM `t_user` ORDER BY `f_id`")
+	assert.Nil(t, err)
+	for rows.Next() {
+		var s *string
+		assert.Nil(t, rows.Scan(&s))
+		names = append(names, s)
+	}
+	assert.Nil(t, rows.Close())
+	if assert.Len(t, names, 6) {
+		assert.Equal(t, "a", *names[0])
+		assert.Equal(t, `b,"c"`, *names[1])
+		assert.Nil(t, names[2])
+		assert.Nil(t, names[3])
+		assert.Equal(t, "d", *names[4])
+		assert.Equal(t, "", *names[5])
+	}
+
+	_, err = LoadCSV(ctx, db, "t_user", []string{"f_id", "f_name"}, strings.NewReader("7\n"), LoadOptions{})
+	assert.NotNil(t, err)
+	_, err = LoadCSV(ctx, db, "t_user", []string{"f_id", "f_name"}, strings.NewReader(""), LoadOptions{Delimiter: '"'})
+	assert.NotNil(t, err)
+}
+
+func TestLoadCSVDM(t *testing.T) {
+	for _, array := range []bool{true, false} {
+		db, mock := newDMMock(t, array)
+		if array {
+			mock.ExpectExec(`INSERT INTO "t_user" \("f_id", "f_name"\) VALUES \(\?, \?\)$`).
+				WithArgs([][]interface{}{{"1", "a"}, {"2", nil}}).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		} else {
+			mock.ExpectExec(`INSERT INTO "t_user" \("f_id", "f_name"\) VALUES \(\?, \?\), \(\?, \?\)$`).
+				WithArgs("1", "a", "2", nil).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+		}
+		n, err := LoadCSV(context.Background(), db, "t_user", []string{"f_id", "f_name"},
+			strings.NewReader("id,name\n1,a\n2,\n"), LoadOptions{Header: true})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	}
+}
+
+func TestLoadCSVMySQL(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+	d, _ := rds.DialectFor("TIDB")
+	db.SetDialect(d)
+
+	mock.ExpectExec("LOAD DATA LOCAL INFILE 'Reader::proton-rds-load-\\d+' INTO TABLE `t_user` " +
+		"FIELDS TERMINATED BY '\t' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '' LINES TERMINATED BY '\\\\n' \\(`f_id`, `f_name`\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	n, err := LoadCSV(context.Background(), db, "t_user", []string{"f_id", "f_name"},
+		strings.NewReader("id\tname\n1\ta\n2\tb\n"), LoadOptions{Delimiter: '\t', Header: true})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteLoadData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  LoadOptions
+		want  string
+	}{
+		// 与 COPY 相同，"" 为空字符串，未包围的空字段为 NULL
+		{"empty", "id,name\n1,\"\"\n2,\n", LoadOptions{Delimiter: ',', Header: true}, "\"1\",\"\"\n\"2\",NULL\n"},
+		{"quoted", "1,\"a,\"\"b\"\"\nc\"\r\n2,NULL\n", LoadOptions{Delimiter: ','}, "\"1\",\"a,\"\"b\"\"\nc\"\n\"2\",\"NULL\"\n"},
+		{"null", "1\t\\N\n2\t\"\\N\"", LoadOptions{Delimiter: '\t', Null: `\N`}, "\"1\"\tNULL\n\"2\"\t\"\\N\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			assert.Nil(t, writeLoadData(&b, strings.NewReader(tt.input), 2, tt.opts))
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+
+	var b strings.Builder
+	assert.NotNil(t, writeLoadData(&b, strings.NewReader("1,a,b\n"), 2, LoadOptions{Delimiter: ','}))
+	assert.NotNil(t, writeLoadData(&b, strings.NewReader("1,\"a\n"), 2, LoadOptions{Delimiter: ','}))
+}
+
+func TestCopyFromCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		d    rds.Dialect
+		opts LoadOptions
+		want string
+	}{
+		{"csv", rds.KingbaseDialect("pg"), LoadOptions{Delimiter: ','},
+			`COPY "t_user" ("f_id", "f_name") FROM STDIN WITH (FORMAT csv, DELIMITER ',', NULL '')`},
+		{"tsv", rds.KingbaseDialect("oracle"), LoadOptions{Delimiter: '\t', Header: true, Null: "null"},
+			"COPY \"t_user\" (\"f_id\", \"f_name\") FROM STDIN WITH (FORMAT csv, DELIMITER '\t', NULL 'null', HEADER true)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, copyFromCSV(tt.d, "t_user", []string{"f_id", "f_name"}, tt.opts))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// NamedExec 将 query 中的 :name 参数替换为连接的原生占位符后通过主库执行，参数取自 arg，
+// arg 为结构体（按 db 标签匹配，不区分大小写）、结构体指针或 map[string]any
+func (db *DB) NamedExec(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	q, args, err := db.BindNamed(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, q, args...)
+}
+
+// NamedQuery 与 NamedExec 相同，通过读库执行查询
+func (db *DB) NamedQuery(ctx context.Context, query string, arg interface{}) (*sql.Rows, error) {
+	q, args, err := db.BindNamed(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryContext(ctx, q, args...)
+}
+
+// BindNamed 返回将 :name 替换为原生占位符（MySQL 系和 DM8 为 ?，Kingbase 为 $n）后的语句和按顺序排列的参数。
+// 引号、反引号、注释中的内容以及 :: 类型转换、:= 赋值不会被替换。
+// 通过 SetInExpansion 开启自动展开时，切片参数在此展开为多个占位符，参数个数超过上限时返回 ErrTooManyParams
+func (db *DB) BindNamed(ctx context.Context, query string, arg interface{}) (string, []interface{}, error) {
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	o := db.in.Load()
+	q, args, err := bindNamed(d, query, arg, o)
+	if err != nil || o == nil {
+		return q, args, err
+	}
+	if limit := db.paramLimit(ctx, len(args), *o); limit > 0 && len(args) > limit {
+		return "", nil, fmt.Errorf("%w: %d exceeds %d", ErrTooManyParams, len(args), limit)
+	}
+	return q, args, nil
+}
+
+// bindNamed 按 d 的占位符绑定命名参数，o 不为空时展开切片参数，
+// 展开后不再有切片参数，执行时不会按只识别 ? 的规则再次展开
+func bindNamed(d rds.Dialect, query string, arg interface{}, o *InOptions) (string, []interface{}, error) {
+	parts, names := compileNamed(query, backslashEscapes(d))
+	lookup, err := namedLookup(arg)
+	if err != nil {
+		return "", nil, err
+	}
+	var b strings.Builder
+	args := make([]interface{}, 0, len(names))
+	placeholder := func(v interface{}) {
+		args = append(args, v)
+		b.WriteString(d.Placeholder(len(args)))
+	}
+	for i, name := range names {
+		v, ok := lookup(name)
+		if !ok {
+			return "", nil, fmt.Errorf("sqlx: missing named parameter %q", name)
+		}
+		b.WriteString(parts[i])
+		sv, ok := sliceValue(v)
+		if !ok || o == nil {
+			placeholder(v)
+			continue
+		}
+		if sv.Len() == 0 {
+			if !o.EmptyAsNull {
+				return "", nil, ErrEmptyIn
+			}
+			b.WriteString("NULL")
+			continue
+		}
+		for j := 0; j < sv.Len(); j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			placeholder(sv.Index(j).Interface())
+		}
+	}
+	b.WriteString(parts[len(parts)-1])
+	return b.String(), args, nil
+}
+
+// backslashEscapes 判断字符串字面量中的反斜杠是否为转义符，MySQL 系默认如此
+func backslashEscapes(d rds.Dialect) bool {
+	switch d.Name() {
+	case "MYSQL", "MARIADB", "GOLDENDB", "TIDB", "OCEANBASE":
+		return true
+	}
+	return false
+}
+
+// compileNamed 拆分语句中的 :name 参数，返回参数之间的语句片段（比参数多一个）和参数名
+func compileNamed(query string, backslash bool) (parts, names []string) {
+	start := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(query) && query[i] != c; i++ {
+				if backslash && query[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			// ::type 类型转换
+			i++
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			end := i + 2
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			parts = append(parts, query[start:i])
+			names = append(names, query[i+1:end])
+			start = end
+			i = end - 1
+		}
+	}
+	return append(parts, query[start:]), names
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9') || c == '.'
+}
+
+// namedLookup 返回按参数名取值的函数
+func namedLookup(arg interface{}) (func(name string) (interface{}, bool), error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		return func(name string) (interface{}, bool) {
+			v, ok := m[name]
+			return v, ok
+		}, nil
+	}
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !isStruct(v.Type()) {
+		return nil, fmt.Errorf("sqlx: named parameters require a struct or map[string]any, got %T", arg)
+	}
+	fields := fieldsOf(v.Type())
+	return func(name string) (interface{}, bool) {
+		index, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, false
+		}
+		f, ok := fieldValue(v, index)
+		if !ok {
+			// 嵌入的结构体指针为空时，其字段的值为 NULL
+			return nil, true
+		}
+		return f.Interface(), true
+	}, nil
+}
+
+// fieldValue 按字段路径取得字段的值，不分配为空的嵌入结构体指针
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,135 @@
+package sqlx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+func TestCompileNamed(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		backslash bool
+		parts     []string
+		names     []string
+	}{
+		{"simple", "SELECT * FROM t WHERE a = :a AND b = :b_1", false,
+			[]string{"SELECT * FROM t WHERE a = ", " AND b = ", ""}, []string{"a", "b_1"}},
+		{"repeated", "UPDATE t SET a = :a WHERE a <> :a", false,
+			[]string{"UPDATE t SET a = ", " WHERE a <> ", ""}, []string{"a", "a"}},
+		{"quoted", `SELECT ':a', ":b", ` + "`:c`" + ` FROM t WHERE d = :d`, false,
+			[]string{`SELECT ':a', ":b", ` + "`:c`" + ` FROM t WHERE d = `, ""}, []string{"d"}},
+		{"backslash", `SELECT 'x\':a' FROM t WHERE d = :d`, true,
+			[]string{`SELECT 'x\':a' FROM t WHERE d = `, ""}, []string{"d"}},
+		{"comments", "SELECT 1 -- :a\nFROM t /* :b */ WHERE c = :c", false,
+			[]string{"SELECT 1 -- :a\nFROM t /* :b */ WHERE c = ", ""}, []string{"c"}},
+		{"casts", "SELECT :a::text, x := 1, 'a'::varchar FROM t", false,
+			[]string{"SELECT ", "::text, x := 1, 'a'::varchar FROM t"}, []string{"a"}},
+		{"none", "SELECT 1", false, []string{"SELECT 1"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, names := compileNamed(tt.query, tt.backslash)
+			assert.Equal(t, tt.parts, parts)
+			assert.Equal(t, tt.names, names)
+		})
+	}
+}
+
+type namedUser struct {
+	ID   int64  `db:"f_id"`
+	Name string `db:"f_name"`
+	*ScanAudit
+}
+
+func TestBindNamed(t *testing.T) {
+	mysql, _ := rds.DialectFor("MYSQL")
+	kdb, _ := rds.DialectFor("KDB9")
+	query := "UPDATE t_user SET f_name = :f_name WHERE f_id = :F_ID AND f_name <> :f_name"
+
+	q, args, err := bindNamed(mysql, query, namedUser{ID: 1, Name: "a"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE t_user SET f_name = ? WHERE f_id = ? AND f_name <> ?", q)
+	assert.Equal(t, []interface{}{"a", int64(1), "a"}, args)
+
+	q, args, err = bindNamed(kdb, query, map[string]interface{}{"f_name": "b", "F_ID": 2}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE t_user SET f_name = $1 WHERE f_id = $2 AND f_name <> $3", q)
+	assert.Equal(t, []interface{}{"b", 2, "b"}, args)
+
+	_, args, err = bindNamed(mysql, "SELECT :f_operator", &namedUser{}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil}, args)
+
+	_, _, err = bindNamed(mysql, query, map[string]interface{}{"f_name": "b"}, nil)
+	assert.NotNil(t, err)
+	_, _, err = bindNamed(mysql, query, 1, nil)
+	assert.NotNil(t, err)
+
+	// 切片参数在绑定时展开，$n 按展开后的位置编号
+	in := "SELECT * FROM t_user WHERE f_id IN (:ids) AND f_name <> :f_name"
+	q, args, err = bindNamed(kdb, in, map[string]interface{}{"ids": []int{1, 2}, "f_name": "c"}, &InOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM t_user WHERE f_id IN ($1, $2) AND f_name <> $3", q)
+	assert.Equal(t, []interface{}{1, 2, "c"}, args)
+	_, _, err = bindNamed(kdb, in, map[string]interface{}{"ids": []int{}, "f_name": "c"}, &InOptions{})
+	assert.Equal(t, ErrEmptyIn, err)
+	q, args, err = bindNamed(kdb, in, map[string]interface{}{"ids": []int{}, "f_name": "c"}, &InOptions{EmptyAsNull: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM t_user WHERE f_id IN (NULL) AND f_name <> $1", q)
+	assert.Equal(t, []interface{}{"c"}, args)
+}
+
+func TestNamedExec(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE t_user (f_id INTEGER PRIMARY KEY, f_name VARCHAR(32))")
+	assert.Nil(t, err)
+
+	_, err = db.NamedExec(ctx, "INSERT INTO t_user (f_id, f_name) VALUES (:f_id, :f_name)", namedUser{ID: 1, Name: "a"})
+	assert.Nil(t, err)
+	_, err = db.NamedExec(ctx, "INSERT INTO t_user (f_id, f_name) VALUES (:f_id, ':f_name')", map[string]interface{}{"f_id": 2})
+	assert.Nil(t, err)
+
+	rows, err := db.NamedQuery(ctx, "SELECT f_name FROM t_user WHERE f_id >= :min ORDER BY f_id", map[string]interface{}{"min": 1})
+	assert.Nil(t, err)
+	var names []string
+	assert.Nil(t, scanAll(rows, reflect.ValueOf(&names).Elem()))
+	assert.Nil(t, rows.Close())
+	assert.Equal(t, []string{"a", ":f_name"}, names)
+
+	mockDB, mock, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	_, _, err = mockDB.BindNamed(ctx, "SELECT :a", map[string]interface{}{"a": 1})
+	assert.NotNil(t, err)
+	dm, _ := rds.DialectFor("DM8")
+	mockDB.SetDialect(dm)
+	mock.ExpectExec(`UPDATE t_user SET f_name = \? WHERE f_id = \?`).WithArgs("a", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = mockDB.NamedExec(ctx, "UPDATE t_user SET f_name = :f_name WHERE f_id = :f_id", &namedUser{ID: 1, Name: "a"})
+	assert.Nil(t, err)
+
+	// $n 占位符的方言中切片参数在绑定时展开，执行时不再展开
+	kdb, _ := rds.DialectFor("KDB9")
+	mockDB.SetDialect(kdb)
+	mockDB.SetInExpansion(&InOptions{})
+	mock.ExpectQuery(`SELECT f_name FROM t_user WHERE f_id IN \(\$1, \$2, \$3\) AND f_name <> \$4`).
+		WithArgs(1, 2, 3, "c").WillReturnRows(sqlmock.NewRows([]string{"f_name"}).AddRow("a"))
+	rows, err = mockDB.NamedQuery(ctx, "SELECT f_name FROM t_user WHERE f_id IN (:ids) AND f_name <> :f_name",
+		map[string]interface{}{"ids": []int{1, 2, 3}, "f_name": "c"})
+	assert.Nil(t, err)
+	assert.Nil(t, rows.Close())
+	mockDB.SetInExpansion(&InOptions{MaxParams: 2})
+	_, err = mockDB.NamedExec(ctx, "DELETE FROM t_user WHERE f_id IN (:ids)", map[string]interface{}{"ids": []int{1, 2, 3}})
+	assert.ErrorIs(t, err, ErrTooManyParams)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,218 @@
+package sqlx
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// ErrInvalidPageToken 为无法解析或与分页方式不匹配的翻页令牌
+var ErrInvalidPageToken = errors.New("sqlx: invalid page token")
+
+// PageQuery 为分页查询参数
+type PageQuery struct {
+	// Size 为每页行数
+	Size int
+	// Token 为上一页返回的 NextToken，第一页为空
+	Token string
+}
+
+// Page 为一页查询结果，NextToken 为空时表示没有下一页
+type Page[T any] struct {
+	Items     []T
+	NextToken string
+}
+
+// Keyset 描述按键值翻页的排序列，Key 返回一行数据中与 Columns 对应的值
+type Keyset[T any] struct {
+	Columns []string
+	Desc    bool
+	Key     func(item T) []any
+}
+
+// pageToken 为翻页令牌的内容，编码为 base64 的 JSON
+type pageToken struct {
+	Offset int64 `json:"o,omitempty"`
+	Key    []any `json:"k,omitempty"`
+}
+
+// QueryPage 按偏移量分页查询，query 需包含 ORDER BY，分页子句按连接的方言追加在 query 之后：
+// MySQL 系为 LIMIT/OFFSET，DM8 和 Kingbase 非 mysql 模式为 OFFSET/FETCH
+func QueryPage[T any](ctx context.Context, db *DB, query string, args []any, page PageQuery, scan func(*sql.Rows) (T, error)) (*Page[T], error) {
+	if page.Size <= 0 {
+		return nil, fmt.Errorf("sqlx: invalid page size %d", page.Size)
+	}
+	token, err := decodePageToken(page.Token)
+	if err != nil {
+		return nil, err
+	}
+	if token.Key != nil {
+		return nil, ErrInvalidPageToken
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多查一行判断是否还有下一页
+	query += " " + d.Paginate(int64(page.Size)+1, token.Offset)
+	items, err := queryItems(ctx, db, query, args, page.Size+1, scan)
+	if err != nil {
+		return nil, err
+	}
+	result := &Page[T]{Items: items}
+	if len(items) > page.Size {
+		result.Items = items[:page.Size]
+		result.NextToken = encodePageToken(pageToken{Offset: token.Offset + int64(page.Size)})
+	}
+	return result, nil
+}
+
+// QueryKeysetPage 按键值分页查询，query 作为子查询，其结果需包含 keyset.Columns 的列，生成的语句为：
+// SELECT * FROM (query) T WHERE (a, b) > (?, ?) ORDER BY a, b，DM8 不支持行比较，展开为 a > ? OR (a = ? AND b > ?)
+func QueryKeysetPage[T any](ctx context.Context, db *DB, query string, args []any, keyset Keyset[T], page PageQuery, scan func(*sql.Rows) (T, error)) (*Page[T], error) {
+	if page.Size <= 0 {
+		return nil, fmt.Errorf("sqlx: invalid page size %d", page.Size)
+	}
+	if len(keyset.Columns) == 0 || keyset.Key == nil {
+		return nil, errors.New("sqlx: keyset requires columns and a key function")
+	}
+	token, err := decodePageToken(page.Token)
+	if err != nil {
+		return nil, err
+	}
+	if token.Offset != 0 || (token.Key != nil && len(token.Key) != len(keyset.Columns)) {
+		return nil, ErrInvalidPageToken
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args = append([]any(nil), args...)
+	q := "SELECT * FROM (" + query + ") T"
+	if token.Key != nil {
+		var where string
+		where, args = keysetWhere(d, keyset.Columns, keyset.Desc, token.Key, args)
+		q += " WHERE " + where
+	}
+	order := make([]string, len(keyset.Columns))
+	for i, c := range keyset.Columns {
+		order[i] = d.QuoteIdentifier(c)
+		if keyset.Desc {
+			order[i] += " DESC"
+		}
+	}
+	q += " ORDER BY " + strings.Join(order, ", ") + " " + d.Paginate(int64(page.Size)+1, 0)
+
+	items, err := queryItems(ctx, db, q, args, page.Size+1, scan)
+	if err != nil {
+		return nil, err
+	}
+	result := &Page[T]{Items: items}
+	if len(items) > page.Size {
+		result.Items = items[:page.Size]
+		result.NextToken = encodePageToken(pageToken{Key: keyset.Key(result.Items[page.Size-1])})
+	}
+	return result, nil
+}
+
+// keysetWhere 返回大于（Desc 时为小于）上一页最后一行键值的条件，以及追加了键值参数的参数列表
+func keysetWhere(d rds.Dialect, columns []string, desc bool, key []any, args []any) (string, []any) {
+	op := " > "
+	if desc {
+		op = " < "
+	}
+	placeholder := func(v any) string {
+		args = append(args, v)
+		return d.Placeholder(len(args))
+	}
+	cols := make([]string, len(columns))
+	for i, c := range columns {
+		cols[i] = d.QuoteIdentifier(c)
+	}
+
+	if d.Name() != "DM8" || len(columns) == 1 {
+		if len(columns) == 1 {
+			return cols[0] + op + placeholder(key[0]), args
+		}
+		ps := make([]string, len(key))
+		for i, v := range key {
+			ps[i] = placeholder(v)
+		}
+		return "(" + strings.Join(cols, ", ") + ")" + op + "(" + strings.Join(ps, ", ") + ")", args
+	}
+
+	// (a, b) > (x, y) 等价于 a > x OR (a = x AND b > y)
+	ors := make([]string, len(columns))
+	for i := range columns {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, cols[j]+" = "+placeholder(key[j]))
+		}
+		ands = append(ands, cols[i]+op+placeholder(key[i]))
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+func queryItems[T any](ctx context.Context, db *DB, query string, args []any, capacity int, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]T, 0, capacity)
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func encodePageToken(token pageToken) string {
+	b, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken 解析翻页令牌，键值中的整数解析为 int64，避免大整数经 float64 丢失精度
+func decodePageToken(s string) (pageToken, error) {
+	var token pageToken
+	if s == "" {
+		return token, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return token, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&token); err != nil {
+		return token, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if token.Offset < 0 {
+		return token, ErrInvalidPageToken
+	}
+	for i, v := range token.Key {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+		if i64, err := n.Int64(); err == nil {
+			token.Key[i] = i64
+		} else if f64, err := n.Float64(); err == nil {
+			token.Key[i] = f64
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,96 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+type pageUser struct {
+	ID  int64
+	Age int
+}
+
+func scanPageUser(rows *sql.Rows) (pageUser, error) {
+	var u pageUser
+	err := rows.Scan(&u.ID, &u.Age)
+	return u, err
+}
+
+func TestQueryPage(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY, `f_age` INTEGER)")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO `t_user` VALUES (1, 30), (2, 20), (3, 20), (4, 10), (5, 30)")
+	assert.Nil(t, err)
+
+	var ids []int64
+	page := PageQuery{Size: 2}
+	for i := 0; ; i++ {
+		p, err := QueryPage(ctx, db, "SELECT `f_id`, `f_age` FROM `t_user` WHERE `f_age` > ? ORDER BY `f_id`", []any{0}, page, scanPageUser)
+		assert.Nil(t, err)
+		for _, u := range p.Items {
+			ids = append(ids, u.ID)
+		}
+		if p.NextToken == "" {
+			assert.Equal(t, 2, i)
+			break
+		}
+		page.Token = p.NextToken
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	ids = nil
+	keyset := Keyset[pageUser]{
+		Columns: []string{"f_age", "f_id"},
+		Key:     func(u pageUser) []any { return []any{u.Age, u.ID} },
+	}
+	page = PageQuery{Size: 2}
+	for {
+		p, err := QueryKeysetPage(ctx, db, "SELECT `f_id`, `f_age` FROM `t_user` WHERE `f_age` > ?", []any{0}, keyset, page, scanPageUser)
+		assert.Nil(t, err)
+		for _, u := range p.Items {
+			ids = append(ids, u.ID)
+		}
+		if p.NextToken == "" {
+			break
+		}
+		page.Token = p.NextToken
+	}
+	assert.Equal(t, []int64{4, 2, 3, 1, 5}, ids)
+
+	_, err = QueryKeysetPage(ctx, db, "SELECT `f_id`, `f_age` FROM `t_user`", nil, keyset, PageQuery{Size: 2, Token: encodePageToken(pageToken{Offset: 2})}, scanPageUser)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	_, err = QueryPage(ctx, db, "SELECT `f_id`, `f_age` FROM `t_user`", nil, PageQuery{Size: 2, Token: "!"}, scanPageUser)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+}
+
+func TestQueryKeysetPageDM(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+	dm, _ := rds.DialectFor("DM8")
+	db.SetDialect(dm)
+
+	keyset := Keyset[pageUser]{
+		Columns: []string{"f_age", "f_id"},
+		Desc:    true,
+		Key:     func(u pageUser) []any { return []any{u.Age, u.ID} },
+	}
+	token := encodePageToken(pageToken{Key: []any{20, int64(9007199254740993)}})
+	mock.ExpectQuery(`SELECT \* FROM \(SELECT f_id, f_age FROM t_user\) T WHERE \(\("f_age" < \?\) OR \("f_age" = \? AND "f_id" < \?\)\) `+
+		`ORDER BY "f_age" DESC, "f_id" DESC OFFSET 0 ROWS FETCH NEXT 2 ROWS ONLY`).
+		WithArgs(int64(20), int64(20), int64(9007199254740993)).
+		WillReturnRows(sqlmock.NewRows([]string{"f_id", "f_age"}).AddRow(1, 10))
+	p, err := QueryKeysetPage(context.Background(), db, "SELECT f_id, f_age FROM t_user", nil, keyset, PageQuery{Size: 1, Token: token}, scanPageUser)
+	assert.Nil(t, err)
+	assert.Equal(t, []pageUser{{ID: 1, Age: 10}}, p.Items)
+	assert.Empty(t, p.NextToken)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+)
+
+// RetryPolicy 为瞬时错误的重试策略。设置后 Query、QueryRow 自动重试，
+// Exec 只在 ctx 通过 WithIdempotent 标记为幂等时重试，事务内的语句不重试
+type RetryPolicy struct {
+	// MaxAttempts 为包含第一次在内的最大执行次数，小于等于 1 时不重试
+	MaxAttempts int
+	// BaseDelay 为第一次重试前的等待时间，之后每次翻倍，实际等待时间在 [delay/2, delay] 之间随机
+	BaseDelay time.Duration
+	// MaxDelay 为单次等待时间的上限，0 表示不限制
+	MaxDelay time.Duration
+	// Retryable 判断错误是否可重试，为空时使用 DefaultRetryable
+	Retryable func(err error) bool
+	// OnRetry 在每次重试前调用，attempt 为即将进行的第几次执行（从 2 开始）
+	OnRetry func(ctx context.Context, attempt int, err error)
+}
+
+// DefaultRetryPolicy 返回默认的重试策略：最多执行 3 次，等待时间从 50ms 开始，最长 1s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+}
+
+// DefaultRetryable 判断错误是否为可重试的瞬时错误：连接断开、死锁和序列化失败，
+// 按 driver.Classify 识别 MySQL 1213、Kingbase 40001/40P01、DM8 网络异常等各数据库的错误
+func DefaultRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	switch rds.Classify(err) {
+	case rds.ConnectionLost, rds.Deadlock, rds.SerializationFailure:
+		return true
+	}
+	return false
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent 标记 ctx 中执行的写操作是幂等的，设置了重试策略时 Exec 出错会自动重试
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// IsIdempotent 返回 ctx 是否通过 WithIdempotent 标记为幂等
+func IsIdempotent(ctx context.Context) bool {
+	v, _ := ctx.Value(idempotentKey{}).(bool)
+	return v
+}
+
+// SetRetryPolicy 设置连接池的重试策略，为空时不重试
+func (db *DB) SetRetryPolicy(p *RetryPolicy) {
+	db.retry.Store(p)
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args, err := db.expandOne(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return db.query(ctx, query, args)
+}
+
+// query 按重试策略通过读库查询，不展开切片参数
+func (db *DB) query(ctx context.Context, query string, args []interface{}) (rows *sql.Rows, err error) {
+	err = db.withRetry(ctx, func() error {
+		rows, err = db.reader.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext 按 row.Err() 判断查询是否出错，Scan 时的错误不会重试。
+// 切片参数展开失败时不执行查询，row.Err() 和 Scan 返回包装了 ErrEmptyIn、ErrTooManyParams 等展开错误的错误
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	q, a, err := db.expandOne(ctx, query, args)
+	if err != nil {
+		// *sql.Row 只能由查询得到，database/sql 转换参数时调用 errArg.Value 返回展开错误，查询不会发送到服务端
+		return db.reader.QueryRowContext(ctx, query, errArg{err})
+	}
+	return db.queryRow(ctx, q, a)
+}
+
+// errArg 为 Value 返回展开错误的参数
+type errArg struct {
+	err error
+}
+
+func (a errArg) Value() (driver.Value, error) {
+	return nil, a.err
+}
+
+// QueryRowErr 与 QueryRowContext 相同，切片参数展开失败时直接返回展开错误
+func (db *DB) QueryRowErr(ctx context.Context, query string, args ...interface{}) (*sql.Row, error) {
+	query, args, err := db.expandOne(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return db.queryRow(ctx, query, args), nil
+}
+
+// queryRow 按重试策略通过读库查询一行，不展开切片参数
+func (db *DB) queryRow(ctx context.Context, query string, args []interface{}) (row *sql.Row) {
+	db.withRetry(ctx, func() error {
+		row = db.reader.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	chunks, err := db.expandChunks(ctx, query, args, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 1 {
+		return db.execChunks(ctx, chunks)
+	}
+	query, args = chunks[0].query, chunks[0].args
+	if !IsIdempotent(ctx) {
+		return db.writer.ExecContext(ctx, query, args...)
+	}
+	err = db.withRetry(ctx, func() error {
+		res, err = db.writer.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+// withRetry 按重试策略执行 f，返回最后一次执行的错误
+func (db *DB) withRetry(ctx context.Context, f func() error) error {
+	p := db.retry.Load()
+	err := f()
+	if p == nil {
+		return err
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	for attempt := 2; attempt <= p.MaxAttempts && err != nil && retryable(err); attempt++ {
+		if p.OnRetry != nil {
+			p.OnRetry(ctx, attempt, err)
+		}
+		t := time.NewTimer(p.backoff(attempt - 1))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		err = f()
+	}
+	return err
+}
+
+// backoff 返回第 n 次重试前的等待时间
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,80 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	var attempts []int
+	db.SetRetryPolicy(&RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		OnRetry:     func(ctx context.Context, attempt int, err error) { attempts = append(attempts, attempt) },
+	})
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	// 读操作自动重试
+	mock.ExpectQuery("SELECT f_name FROM t_user").WillReturnError(deadlock)
+	mock.ExpectQuery("SELECT f_name FROM t_user").WillReturnRows(sqlmock.NewRows([]string{"f_name"}).AddRow("a"))
+	var name string
+	assert.Nil(t, db.QueryRow("SELECT f_name FROM t_user").Scan(&name))
+	assert.Equal(t, "a", name)
+	assert.Equal(t, []int{2}, attempts)
+
+	// 超过最大次数后返回最后一次的错误
+	attempts = nil
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT f_name FROM t_user").WillReturnError(deadlock)
+	}
+	_, err = db.Query("SELECT f_name FROM t_user")
+	assert.Equal(t, deadlock, err)
+	assert.Equal(t, []int{2, 3}, attempts)
+
+	// 不可重试的错误不重试
+	attempts = nil
+	mock.ExpectQuery("SELECT f_name FROM t_user").WillReturnError(&mysql.MySQLError{Number: 1064})
+	_, err = db.Query("SELECT f_name FROM t_user")
+	assert.NotNil(t, err)
+	assert.Empty(t, attempts)
+
+	// 未标记幂等的写操作不重试
+	mock.ExpectExec("UPDATE t_user").WillReturnError(deadlock)
+	_, err = db.Exec("UPDATE t_user SET f_name = ?", "b")
+	assert.Equal(t, deadlock, err)
+	assert.Empty(t, attempts)
+
+	mock.ExpectExec("UPDATE t_user").WillReturnError(deadlock)
+	mock.ExpectExec("UPDATE t_user").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = db.ExecContext(WithIdempotent(context.Background()), "UPDATE t_user SET f_name = ?", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2}, attempts)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	assert.True(t, DefaultRetryable(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, DefaultRetryable(mysql.ErrInvalidConn))
+	assert.False(t, DefaultRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, DefaultRetryable(context.DeadlineExceeded))
+	assert.False(t, DefaultRetryable(errors.New("other")))
+}
+
+func TestBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for n, max := range map[int]time.Duration{1: 100, 2: 200, 3: 300, 10: 300} {
+		d := p.backoff(n)
+		assert.True(t, d >= max*time.Millisecond/2 && d <= max*time.Millisecond, "n=%d d=%v", n, d)
+	}
+}
@@ -0,0 +1,219 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Get 执行查询并将第一行扫描到 dest，通过读库执行，没有结果时返回 sql.ErrNoRows。
+// dest 为结构体指针时按列名映射到字段，否则结果只能有一列，扫描到 dest 本身
+func (db *DB) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("sqlx: Get requires a non-nil pointer, got %T", dest)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	s, err := newRowScanner(rows, v.Elem().Type())
+	if err != nil {
+		return err
+	}
+	if err = s.scan(rows, v.Elem()); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// Select 执行查询并将所有行扫描到 dest 指向的切片，通过读库执行，元素可为结构体、结构体指针或单列的值。
+// 结构体字段按 db 标签映射，没有标签时为字段名，列名不区分大小写，匿名嵌入的结构体字段展开，
+// 指针字段在值为 NULL 时为 nil
+func (db *DB) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("sqlx: Select requires a pointer to a slice, got %T", dest)
+	}
+	chunks, err := db.expandChunks(ctx, query, args, true)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(reflect.MakeSlice(v.Elem().Type(), 0, 0))
+	for _, c := range chunks {
+		if err = db.selectChunk(ctx, v.Elem(), c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) selectChunk(ctx context.Context, slice reflect.Value, c inQuery) error {
+	rows, err := db.query(ctx, c.query, c.args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanAll(rows, slice)
+}
+
+// scanAll 将 rows 的所有行追加到切片 slice
+func scanAll(rows *sql.Rows, slice reflect.Value) error {
+	elemType := slice.Type().Elem()
+	ptr := elemType.Kind() == reflect.Pointer
+	baseType := elemType
+	if ptr {
+		baseType = elemType.Elem()
+	}
+	result := slice
+	var s *rowScanner
+	for rows.Next() {
+		if s == nil {
+			var err error
+			if s, err = newRowScanner(rows, baseType); err != nil {
+				return err
+			}
+		}
+		item := reflect.New(baseType)
+		if err := s.scan(rows, item.Elem()); err != nil {
+			return err
+		}
+		if ptr {
+			result = reflect.Append(result, item)
+		} else {
+			result = reflect.Append(result, item.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	slice.Set(result)
+	return nil
+}
+
+// rowScanner 记录每一列对应的字段
+type rowScanner struct {
+	fields [][]int // 为空时扫描到值本身
+}
+
+func newRowScanner(rows *sql.Rows, typ reflect.Type) (*rowScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !isStruct(typ) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("sqlx: cannot scan %d columns into %s", len(columns), typ)
+		}
+		return &rowScanner{}, nil
+	}
+	m := fieldsOf(typ)
+	s := &rowScanner{fields: make([][]int, len(columns))}
+	for i, c := range columns {
+		index, ok := m[strings.ToLower(c)]
+		if !ok {
+			return nil, fmt.Errorf("sqlx: no field in %s for column %s", typ, c)
+		}
+		s.fields[i] = index
+	}
+	return s, nil
+}
+
+func (s *rowScanner) scan(rows *sql.Rows, v reflect.Value) error {
+	if s.fields == nil {
+		return rows.Scan(v.Addr().Interface())
+	}
+	dest := make([]interface{}, len(s.fields))
+	for i, index := range s.fields {
+		dest[i] = fieldByIndex(v, index).Addr().Interface()
+	}
+	return rows.Scan(dest...)
+}
+
+// fieldByIndex 按字段路径取得字段，为空的嵌入结构体指针会被分配
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// isStruct 判断 typ 是否需要按字段扫描，实现了 sql.Scanner 的类型和 time.Time 作为单个值扫描
+func isStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ != timeType && !reflect.PointerTo(typ).Implements(scannerType)
+}
+
+var fieldCache sync.Map // reflect.Type -> map[string][]int
+
+// fieldsOf 返回结构体的小写列名到字段路径的映射，外层字段优先于嵌入结构体中的同名字段
+func fieldsOf(typ reflect.Type) map[string][]int {
+	if m, ok := fieldCache.Load(typ); ok {
+		return m.(map[string][]int)
+	}
+	m := make(map[string][]int)
+	addFields(m, typ, nil)
+	fieldCache.Store(typ, m)
+	return m
+}
+
+func addFields(m map[string][]int, typ reflect.Type, prefix []int) {
+	var embedded []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && tag == "" && isStruct(ft) {
+			// 未导出类型的嵌入指针无法分配，与 encoding/json 一样忽略
+			if f.IsExported() || f.Type.Kind() != reflect.Pointer {
+				embedded = append(embedded, f)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		name = strings.ToLower(name)
+		if _, ok := m[name]; !ok {
+			m[name] = append(append([]int(nil), prefix...), i)
+		}
+	}
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		addFields(m, ft, append(append([]int(nil), prefix...), f.Index...))
+	}
+}
@@ -0,0 +1,93 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type scanBase struct {
+	ID        int64     `db:"f_id"`
+	CreatedAt time.Time `db:"f_created_at"`
+}
+
+type ScanAudit struct {
+	Operator string `db:"f_operator"`
+}
+
+type scanUser struct {
+	scanBase
+	*ScanAudit
+	Name    string `db:"f_name"`
+	Age     *int   `db:"f_age"`
+	Email   sql.NullString
+	Ignored string `db:"-"`
+}
+
+func TestGetSelect(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE t_user (f_id INTEGER PRIMARY KEY, f_name VARCHAR(32), f_age INTEGER, " +
+		"email VARCHAR(64), f_operator VARCHAR(32), f_created_at DATETIME)")
+	assert.Nil(t, err)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err = db.Exec("INSERT INTO t_user VALUES (1, 'a', 10, 'a@x', 'admin', ?), (2, 'b', NULL, NULL, NULL, ?)", now, now)
+	assert.Nil(t, err)
+
+	var u scanUser
+	assert.Nil(t, db.Get(ctx, &u, "SELECT * FROM t_user WHERE f_id = ?", 1))
+	assert.Equal(t, int64(1), u.ID)
+	assert.Equal(t, "a", u.Name)
+	assert.Equal(t, 10, *u.Age)
+	assert.Equal(t, "a@x", u.Email.String)
+	assert.Equal(t, "admin", u.Operator)
+	assert.True(t, now.Equal(u.CreatedAt))
+
+	var users []*scanUser
+	assert.Nil(t, db.Select(ctx, &users, "SELECT f_id, f_name, f_age FROM t_user ORDER BY f_id"))
+	assert.Equal(t, 2, len(users))
+	assert.Nil(t, users[1].Age)
+	assert.Nil(t, users[1].ScanAudit)
+
+	var names []string
+	assert.Nil(t, db.Select(ctx, &names, "SELECT f_name FROM t_user ORDER BY f_id"))
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	var count int
+	assert.Nil(t, db.Get(ctx, &count, "SELECT COUNT(*) FROM t_user"))
+	assert.Equal(t, 2, count)
+
+	var values []scanUser
+	assert.Nil(t, db.Select(ctx, &values, "SELECT f_id FROM t_user WHERE f_id > 10"))
+	assert.NotNil(t, values)
+	assert.Empty(t, values)
+
+	assert.Equal(t, sql.ErrNoRows, db.Get(ctx, &u, "SELECT * FROM t_user WHERE f_id = 3"))
+	assert.NotNil(t, db.Get(ctx, &u, "SELECT f_id, 1 AS f_unknown FROM t_user"))
+	assert.NotNil(t, db.Get(ctx, &count, "SELECT f_id, f_name FROM t_user"))
+	assert.NotNil(t, db.Get(ctx, u, "SELECT * FROM t_user"))
+	assert.NotNil(t, db.Select(ctx, &u, "SELECT * FROM t_user"))
+}
+
+func TestSelectUpperCaseColumns(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// DM8 返回大写的列名
+	mock.ExpectQuery("SELECT f_id, f_name FROM t_user").
+		WillReturnRows(sqlmock.NewRows([]string{"F_ID", "F_NAME"}).AddRow(1, "a").AddRow(2, "b"))
+	var users []scanUser
+	assert.Nil(t, db.Select(ctx, &users, "SELECT f_id, f_name FROM t_user"))
+	assert.Equal(t, 2, len(users))
+	assert.Equal(t, int64(2), users[1].ID)
+	assert.Equal(t, "b", users[1].Name)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -17,6 +17,7 @@ type DBConfig struct {
 	MaxOpenReadConns int    `yaml:"max_open_read_conns"`
 	ConnMaxLifeTime  int    `yaml:"conn_max_life_time_s"`
 	CustomDriver     string `yaml:"custom_driver"`
+	DBType           string `yaml:"db_type"`
 	ParseTime        string `yaml:"parseTime"`
 	Loc              string `yaml:"loc"`
 }
@@ -0,0 +1,118 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var errEmptyRow = errors.New("sqlx: upsert row is empty")
+
+// Upsert 插入一行数据，keyCols 对应的唯一键或主键冲突时更新其余列。按连接的方言生成：
+// MySQL/TiDB/GoldenDB 为 INSERT ... ON DUPLICATE KEY UPDATE，DM8 为 MERGE INTO，
+// Kingbase 为 INSERT ... ON CONFLICT（mysql 模式为 ON DUPLICATE KEY UPDATE）
+// 注意 MySQL 系的 RowsAffected 在插入时为 1，更新时为 2
+func Upsert(ctx context.Context, db *DB, table string, keyCols []string, row map[string]any) (sql.Result, error) {
+	columns, err := upsertColumns(keyCols, row)
+	if err != nil {
+		return nil, err
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]any, len(columns))
+	for i, c := range columns {
+		args[i] = row[c]
+	}
+	return db.ExecContext(ctx, d.Upsert(table, columns, keyCols, 1), args...)
+}
+
+// UpsertBatch 批量 upsert，所有行的列必须相同，返回各语句 RowsAffected 之和
+// 参数个数超过主库 Capabilities 的 MaxParams 时分为多条语句，并在同一个事务中执行
+func UpsertBatch(ctx context.Context, db *DB, table string, keyCols []string, rows []map[string]any) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	columns, err := upsertColumns(keyCols, rows[0])
+	if err != nil {
+		return 0, err
+	}
+	for i, row := range rows[1:] {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("sqlx: upsert row %d has %d columns, want %d", i+1, len(row), len(columns))
+		}
+		for _, c := range columns {
+			if _, ok := row[c]; !ok {
+				return 0, fmt.Errorf("sqlx: upsert row %d is missing column %q", i+1, c)
+			}
+		}
+	}
+	d, err := db.Dialect(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	chunk := max(db.maxParams(ctx)/len(columns), 1)
+	exec := func(ctx context.Context, e interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	}) (int64, error) {
+		var affected int64
+		for start := 0; start < len(rows); start += chunk {
+			end := min(start+chunk, len(rows))
+			args := make([]any, 0, (end-start)*len(columns))
+			for _, row := range rows[start:end] {
+				for _, c := range columns {
+					args = append(args, row[c])
+				}
+			}
+			res, err := e.ExecContext(ctx, d.Upsert(table, columns, keyCols, end-start), args...)
+			if err != nil {
+				return affected, err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return affected, err
+			}
+			affected += n
+		}
+		return affected, nil
+	}
+	if len(rows) <= chunk {
+		return exec(ctx, db)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := exec(ctx, tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return affected, tx.Commit()
+}
+
+// upsertColumns 返回按列名排序的列，并检查 keyCols 都在 row 中
+func upsertColumns(keyCols []string, row map[string]any) ([]string, error) {
+	if len(row) == 0 {
+		return nil, errEmptyRow
+	}
+	if len(keyCols) == 0 {
+		return nil, errors.New("sqlx: upsert requires at least one key column")
+	}
+	for _, k := range keyCols {
+		if _, ok := row[k]; !ok {
+			return nil, fmt.Errorf("sqlx: upsert key column %q is missing from row", k)
+		}
+	}
+	columns := make([]string, 0, len(row))
+	for c := range row {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	return columns, nil
+}
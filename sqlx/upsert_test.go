@@ -0,0 +1,97 @@
+package sqlx
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	rds "github.com/kweaver-ai/proton-rds-sdk-go/driver"
+	_ "github.com/kweaver-ai/proton-rds-sdk-go/driver/sqlite"
+)
+
+func newSQLiteDB(t *testing.T) *DB {
+	db, err := NewDB(&DBConfig{
+		User:     "user",
+		Password: "pwd",
+		Host:     "localhost",
+		Database: filepath.Join(t.TempDir(), "test.db"),
+		DBType:   "sqlite",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestUpsert(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE `t_user` (`f_id` INTEGER PRIMARY KEY, `f_name` VARCHAR(32), `f_age` INTEGER)")
+	assert.Nil(t, err)
+
+	_, err = Upsert(ctx, db, "t_user", []string{"f_id"}, map[string]any{"f_id": 1, "f_name": "a", "f_age": 10})
+	assert.Nil(t, err)
+	_, err = Upsert(ctx, db, "t_user", []string{"f_id"}, map[string]any{"f_id": 1, "f_name": "b", "f_age": 11})
+	assert.Nil(t, err)
+	n, err := UpsertBatch(ctx, db, "t_user", []string{"f_id"}, []map[string]any{
+		{"f_id": 1, "f_name": "c", "f_age": 12},
+		{"f_id": 2, "f_name": "d", "f_age": 13},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+
+	var name string
+	var count int
+	assert.Nil(t, db.QueryRow("SELECT `f_name` FROM `t_user` WHERE `f_id` = ?", 1).Scan(&name))
+	assert.Equal(t, "c", name)
+	assert.Nil(t, db.QueryRow("SELECT COUNT(*) FROM `t_user`").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	_, err = Upsert(ctx, db, "t_user", []string{"f_uid"}, map[string]any{"f_id": 1})
+	assert.NotNil(t, err)
+	_, err = UpsertBatch(ctx, db, "t_user", []string{"f_id"}, []map[string]any{{"f_id": 1, "f_name": "e"}, {"f_id": 2, "f_age": 1}})
+	assert.NotNil(t, err)
+
+	c, err := db.Capabilities(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "SQLITE", c.DBType)
+}
+
+func TestUpsertBatchChunk(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteDB(t)
+	_, err := db.Exec("CREATE TABLE `t_wide` (`c0` INTEGER PRIMARY KEY, `c1` INTEGER, `c2` INTEGER, `c3` INTEGER, `c4` INTEGER, `c5` INTEGER, `c6` INTEGER)")
+	assert.Nil(t, err)
+
+	// 7 列时 32767 个参数正好是整行，SQLite 的上限为 32766，每条语句 4680 行
+	rows := make([]map[string]any, 5000)
+	for i := range rows {
+		rows[i] = map[string]any{"c0": i, "c1": i, "c2": i, "c3": i, "c4": i, "c5": i, "c6": i}
+	}
+	n, err := UpsertBatch(ctx, db, "t_wide", []string{"c0"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5000), n)
+}
+
+func TestUpsertDialect(t *testing.T) {
+	db, mock, err := New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	_, err = Upsert(context.Background(), db, "t_user", []string{"f_id"}, map[string]any{"f_id": 1})
+	assert.NotNil(t, err)
+
+	dm, _ := rds.DialectFor("DM8")
+	db.SetDialect(dm)
+	mock.ExpectExec(`MERGE INTO "t_user" T USING \(SELECT \? AS "f_id", \? AS "f_name" FROM DUAL\) S ON \(T."f_id" = S."f_id"\) `+
+		`WHEN MATCHED THEN UPDATE SET T."f_name" = S."f_name" WHEN NOT MATCHED THEN INSERT \("f_id", "f_name"\) VALUES \(S."f_id", S."f_name"\)`).
+		WithArgs(1, "a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = Upsert(context.Background(), db, "t_user", []string{"f_id"}, map[string]any{"f_id": 1, "f_name": "a"})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,332 @@
+// Package temporal 提供可在 MySQL 系、DM8、Kingbase、PostgreSQL 和 SQLite 间移植的日期时间类型。
+// 各类型基于 civil 包，可从驱动返回的 time.Time、[]byte、string 以及 gokb 的 civil.Date、civil.Time 扫描，
+// 扫描时保留纳秒精度
+package temporal
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-sql/civil"
+)
+
+var (
+	location  atomic.Pointer[time.Location]
+	precision atomic.Int32
+)
+
+func init() {
+	precision.Store(6)
+}
+
+// SetLocation 设置解析不带时区的文本和 civil 值时使用的时区，默认为 time.Local
+func SetLocation(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Location 返回 SetLocation 设置的时区
+func Location() *time.Location {
+	if loc := location.Load(); nil != loc {
+		return loc
+	}
+	return time.Local
+}
+
+// SetPrecision 设置 Value 写入时保留的秒的小数位数，取值为 0 到 9，默认为 6 即微秒，超出的部分截断
+func SetPrecision(digits int) {
+	precision.Store(int32(min(max(digits, 0), 9)))
+}
+
+// fraction 返回按 SetPrecision 格式化秒的小数部分的格式，末尾的 0 省略
+func fraction() string {
+	if p := int(precision.Load()); p > 0 {
+		return "." + strings.Repeat("9", p)
+	}
+	return ""
+}
+
+// truncate 按 SetPrecision 截断 t 的纳秒部分
+func truncate(t time.Time) time.Time {
+	d := time.Duration(1)
+	for i := precision.Load(); i < 9; i++ {
+		d *= 10
+	}
+	return t.Truncate(d)
+}
+
+// dateLayouts 为带日期的文本依次尝试的格式，timeLayouts 为只有时间的文本的格式，
+// 秒后的小数部分可有可无，不带时区的文本按 Location 解析
+var (
+	dateLayouts = []string{
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02T15:04:05Z07:00",
+		"2006-01-02 15:04:05Z07",
+		"2006-01-02 15:04:05 -07:00",
+		"2006-01-02 15:04:05 -0700 MST",
+		"2006-01-02",
+	}
+	timeLayouts = []string{
+		"15:04:05",
+		"15:04:05Z07:00",
+		"15:04:05Z07",
+	}
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// scanTime 将驱动返回的值转换为 time.Time，date 表示值是否包含日期部分，
+// MySQL 的零值日期 0000-00-00 转换为零值 time.Time
+func scanTime(value interface{}, name string) (t time.Time, date bool, err error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true, nil
+	case civil.Date:
+		return v.In(Location()), true, nil
+	case civil.DateTime:
+		return v.In(Location()), true, nil
+	case civil.Time:
+		return time.Date(0, 1, 1, v.Hour, v.Minute, v.Second, v.Nanosecond, Location()), false, nil
+	case []byte:
+		return parse(string(v), name)
+	case string:
+		return parse(v, name)
+	case nil:
+		return t, false, fmt.Errorf("temporal: cannot scan NULL into %s, use Null%s", name, name)
+	}
+	// gokb 的 DateTime1 等以 time.Time 为底层类型的值
+	if rv := reflect.ValueOf(value); rv.Type().ConvertibleTo(timeType) {
+		return rv.Convert(timeType).Interface().(time.Time), true, nil
+	}
+	return t, false, fmt.Errorf("temporal: cannot scan %T into %s", value, name)
+}
+
+// parse 依次按 dateLayouts 和 timeLayouts 解析 s
+func parse(s, name string) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0000-00-00") {
+		return time.Time{}, true, nil
+	}
+	loc := Location()
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); nil == err {
+			return t, true, nil
+		}
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); nil == err {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("temporal: cannot parse %q as %s", s, name)
+}
+
+// scanDate 为要求值包含日期部分的 scanTime
+func scanDate(value interface{}, name string) (time.Time, error) {
+	t, date, err := scanTime(value, name)
+	if nil == err && !date {
+		err = fmt.Errorf("temporal: cannot scan a time of day into %s", name)
+	}
+	return t, err
+}
+
+// Date 为不带时区的日期，对应 DATE 列，Value 写入 2006-01-02 格式的文本
+type Date struct {
+	civil.Date
+}
+
+// DateOf 返回 t 在其时区中的日期
+func DateOf(t time.Time) Date {
+	return Date{civil.DateOf(t)}
+}
+
+// Scan 实现 sql.Scanner，time.Time 取其所在时区的日期
+func (d *Date) Scan(value interface{}) error {
+	t, err := scanDate(value, "Date")
+	if nil != err {
+		return err
+	}
+	d.Date = civil.Date{}
+	if !t.IsZero() {
+		d.Date = civil.DateOf(t)
+	}
+	return nil
+}
+
+// Value 实现 driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// TimeOfDay 为不带日期和时区的时间，对应 TIME 列，Value 写入 15:04:05 格式的文本
+type TimeOfDay struct {
+	civil.Time
+}
+
+// TimeOfDayOf 返回 t 在其时区中的时间
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{civil.TimeOf(t)}
+}
+
+// Scan 实现 sql.Scanner，带日期的值取其时间部分，带时区的 TIME WITH TIME ZONE 忽略时区，
+// MySQL 超出 24 小时或为负的 TIME 返回错误
+func (t *TimeOfDay) Scan(value interface{}) error {
+	v, _, err := scanTime(value, "TimeOfDay")
+	if nil != err {
+		return err
+	}
+	t.Time = civil.TimeOf(v)
+	return nil
+}
+
+// Value 实现 driver.Valuer
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format("15:04:05" + fraction()), nil
+}
+
+// DateTime 为不带时区的日期时间，对应 MySQL/DM8 的 DATETIME 和 TIMESTAMP WITHOUT TIME ZONE 列，
+// Value 写入 2006-01-02 15:04:05 格式的文本，不受驱动和会话时区的影响
+type DateTime struct {
+	civil.DateTime
+}
+
+// DateTimeOf 返回 t 在其时区中的日期时间
+func DateTimeOf(t time.Time) DateTime {
+	return DateTime{civil.DateTimeOf(t)}
+}
+
+// Scan 实现 sql.Scanner，time.Time 和带时区的文本取其所在时区的日期时间
+func (dt *DateTime) Scan(value interface{}) error {
+	t, err := scanDate(value, "DateTime")
+	if nil != err {
+		return err
+	}
+	dt.DateTime = civil.DateTime{}
+	if !t.IsZero() {
+		dt.DateTime = civil.DateTimeOf(t)
+	}
+	return nil
+}
+
+// Value 实现 driver.Valuer
+func (dt DateTime) Value() (driver.Value, error) {
+	return dt.In(time.UTC).Format("2006-01-02 15:04:05" + fraction()), nil
+}
+
+// Timestamp 为带时区的时间点，对应 MySQL 的 TIMESTAMP 和 TIMESTAMP WITH TIME ZONE 列，
+// Value 写入 time.Time，由驱动按连接的时区转换
+type Timestamp struct {
+	time.Time
+}
+
+// Scan 实现 sql.Scanner，不带时区的文本和 civil 值按 Location 解析
+func (ts *Timestamp) Scan(value interface{}) error {
+	t, err := scanDate(value, "Timestamp")
+	if nil != err {
+		return err
+	}
+	ts.Time = t
+	return nil
+}
+
+// Value 实现 driver.Valuer
+func (ts Timestamp) Value() (driver.Value, error) {
+	return truncate(ts.Time), nil
+}
+
+// NullDate 为可以为 NULL 的 Date
+type NullDate struct {
+	Date  Date
+	Valid bool
+}
+
+// Scan 实现 sql.Scanner
+func (n *NullDate) Scan(value interface{}) error {
+	if n.Valid = nil != value; !n.Valid {
+		n.Date = Date{}
+		return nil
+	}
+	return n.Date.Scan(value)
+}
+
+// Value 实现 driver.Valuer
+func (n NullDate) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Date.Value()
+}
+
+// NullTimeOfDay 为可以为 NULL 的 TimeOfDay
+type NullTimeOfDay struct {
+	TimeOfDay TimeOfDay
+	Valid     bool
+}
+
+// Scan 实现 sql.Scanner
+func (n *NullTimeOfDay) Scan(value interface{}) error {
+	if n.Valid = nil != value; !n.Valid {
+		n.TimeOfDay = TimeOfDay{}
+		return nil
+	}
+	return n.TimeOfDay.Scan(value)
+}
+
+// Value 实现 driver.Valuer
+func (n NullTimeOfDay) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.TimeOfDay.Value()
+}
+
+// NullDateTime 为可以为 NULL 的 DateTime
+type NullDateTime struct {
+	DateTime DateTime
+	Valid    bool
+}
+
+// Scan 实现 sql.Scanner
+func (n *NullDateTime) Scan(value interface{}) error {
+	if n.Valid = nil != value; !n.Valid {
+		n.DateTime = DateTime{}
+		return nil
+	}
+	return n.DateTime.Scan(value)
+}
+
+// Value 实现 driver.Valuer
+func (n NullDateTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.DateTime.Value()
+}
+
+// NullTimestamp 为可以为 NULL 的 Timestamp
+type NullTimestamp struct {
+	Timestamp Timestamp
+	Valid     bool
+}
+
+// Scan 实现 sql.Scanner
+func (n *NullTimestamp) Scan(value interface{}) error {
+	if n.Valid = nil != value; !n.Valid {
+		n.Timestamp = Timestamp{}
+		return nil
+	}
+	return n.Timestamp.Scan(value)
+}
+
+// Value 实现 driver.Valuer
+func (n NullTimestamp) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Timestamp.Value()
+}
@@ -0,0 +1,145 @@
+package temporal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+// dateTime1 模拟 gokb 中以 time.Time 为底层类型的值
+type dateTime1 time.Time
+
+func TestScan(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	SetLocation(cst)
+	defer SetLocation(nil)
+
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, cst)
+	tests := []struct {
+		name  string
+		value interface{}
+		date  string
+		clock string
+		dt    string
+		ts    time.Time
+	}{
+		{"time.Time", ts, "2024-05-06", "07:08:09.123456789", "2024-05-06T07:08:09.123456789", ts},
+		{"time.Time utc", ts.UTC(), "2024-05-05", "23:08:09.123456789", "2024-05-05T23:08:09.123456789", ts},
+		{"mysql datetime", []byte("2024-05-06 07:08:09.123456789"), "2024-05-06", "07:08:09.123456789", "2024-05-06T07:08:09.123456789", ts},
+		{"mysql date", []byte("2024-05-06"), "2024-05-06", "00:00:00", "2024-05-06T00:00:00", time.Date(2024, 5, 6, 0, 0, 0, 0, cst)},
+		{"mysql zero", []byte("0000-00-00 00:00:00"), "0000-00-00", "00:00:00", "0000-00-00T00:00:00", time.Time{}},
+		{"iso", "2024-05-06T07:08:09Z", "2024-05-06", "07:08:09", "2024-05-06T07:08:09", time.Date(2024, 5, 6, 15, 8, 9, 0, cst)},
+		{"timestamptz", "2024-05-06 07:08:09.5+08", "2024-05-06", "07:08:09.500000000", "2024-05-06T07:08:09.500000000", time.Date(2024, 5, 6, 7, 8, 9, 5e8, cst)},
+		{"dm8", "2024-05-06 07:08:09.123456 +08:00", "2024-05-06", "07:08:09.123456000", "2024-05-06T07:08:09.123456000", time.Date(2024, 5, 6, 7, 8, 9, 123456000, cst)},
+		{"civil.Date", civil.Date{Year: 2024, Month: 5, Day: 6}, "2024-05-06", "00:00:00", "2024-05-06T00:00:00", time.Date(2024, 5, 6, 0, 0, 0, 0, cst)},
+		{"gokb", dateTime1(ts), "2024-05-06", "07:08:09.123456789", "2024-05-06T07:08:09.123456789", ts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			assert.Nil(t, d.Scan(tt.value))
+			assert.Equal(t, tt.date, d.String())
+			var c TimeOfDay
+			assert.Nil(t, c.Scan(tt.value))
+			assert.Equal(t, tt.clock, c.String())
+			var dt DateTime
+			assert.Nil(t, dt.Scan(tt.value))
+			assert.Equal(t, tt.dt, dt.String())
+			var s Timestamp
+			assert.Nil(t, s.Scan(tt.value))
+			assert.True(t, tt.ts.Equal(s.Time), s.Time)
+		})
+	}
+}
+
+func TestScanTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"mysql", []byte("12:34:56.000789"), "12:34:56.000789000"},
+		{"timetz", "12:34:56+08", "12:34:56"},
+		{"civil.Time", civil.Time{Hour: 12, Minute: 34, Second: 56, Nanosecond: 1}, "12:34:56.000000001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c TimeOfDay
+			assert.Nil(t, c.Scan(tt.value))
+			assert.Equal(t, tt.want, c.String())
+		})
+	}
+
+	var c TimeOfDay
+	assert.NotNil(t, c.Scan([]byte("838:59:59")))
+	assert.NotNil(t, c.Scan("-01:00:00"))
+	var d Date
+	assert.NotNil(t, d.Scan("12:34:56"))
+	assert.NotNil(t, d.Scan(civil.Time{}))
+	assert.NotNil(t, d.Scan("2024/05/06"))
+	assert.NotNil(t, d.Scan(int64(1)))
+	assert.NotNil(t, d.Scan(nil))
+}
+
+func TestValue(t *testing.T) {
+	defer SetPrecision(6)
+	dt := DateTime{civil.DateTime{Date: civil.Date{Year: 2024, Month: 5, Day: 6}, Time: civil.Time{Hour: 7, Minute: 8, Second: 9, Nanosecond: 123456789}}}
+	ts := Timestamp{time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)}
+
+	tests := []struct {
+		precision int
+		dt        string
+		clock     string
+		ts        time.Time
+	}{
+		{6, "2024-05-06 07:08:09.123456", "07:08:09.123456", time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)},
+		{9, "2024-05-06 07:08:09.123456789", "07:08:09.123456789", ts.Time},
+		{0, "2024-05-06 07:08:09", "07:08:09", time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)},
+		{12, "2024-05-06 07:08:09.123456789", "07:08:09.123456789", ts.Time},
+	}
+	for _, tt := range tests {
+		SetPrecision(tt.precision)
+		v, err := dt.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, tt.dt, v)
+		v, err = TimeOfDay{dt.Time}.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, tt.clock, v)
+		v, err = ts.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, tt.ts, v)
+	}
+	v, err := Date{dt.Date}.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-05-06", v)
+}
+
+func TestNull(t *testing.T) {
+	var d NullDate
+	assert.Nil(t, d.Scan("2024-05-06"))
+	assert.True(t, d.Valid)
+	assert.Equal(t, "2024-05-06", d.Date.String())
+	assert.Nil(t, d.Scan(nil))
+	assert.False(t, d.Valid)
+	assert.True(t, d.Date.IsZero())
+	v, err := d.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+
+	var c NullTimeOfDay
+	assert.Nil(t, c.Scan(nil))
+	assert.False(t, c.Valid)
+	var dt NullDateTime
+	assert.Nil(t, dt.Scan([]byte("2024-05-06 07:08:09")))
+	assert.True(t, dt.Valid)
+	v, err = dt.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-05-06 07:08:09", v)
+	var ts NullTimestamp
+	assert.NotNil(t, ts.Scan("bad"))
+	assert.True(t, ts.Valid)
+	assert.Nil(t, ts.Scan(nil))
+	assert.False(t, ts.Valid)
+}